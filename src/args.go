@@ -0,0 +1,61 @@
+package main
+
+import "strings"
+
+// cliArgs holds the positional arguments and the --flags of a command.
+type cliArgs struct {
+	positional []string
+	flags      map[string]string
+}
+
+// parseArgs splits the arguments into positional arguments and flags.
+// Flags listed in valueFlags consume the next argument as their value unless
+// the value is given inline as --flag=value, all other flags are boolean.
+func parseArgs(args []string, valueFlags ...string) cliArgs {
+	parsed := cliArgs{flags: make(map[string]string)}
+
+	takesValue := func(name string) bool {
+		for _, flag := range valueFlags {
+			if flag == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || arg == "--" {
+			parsed.positional = append(parsed.positional, arg)
+			continue
+		}
+
+		name, value, inline := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !inline && takesValue(name) && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		parsed.flags[name] = value
+	}
+
+	return parsed
+}
+
+// has reports whether the flag is present.
+func (a cliArgs) has(name string) bool {
+	_, ok := a.flags[name]
+	return ok
+}
+
+// get returns the value of the flag, or an empty string if it is not set.
+func (a cliArgs) get(name string) string {
+	return a.flags[name]
+}
+
+// arg returns the positional argument at index i, or an empty string.
+func (a cliArgs) arg(i int) string {
+	if i < len(a.positional) {
+		return a.positional[i]
+	}
+	return ""
+}
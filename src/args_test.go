@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		flags      map[string]string
+	}{
+		{
+			name:       "positional only",
+			args:       []string{"bash/cat", "list"},
+			positional: []string{"bash/cat", "list"},
+			flags:      map[string]string{},
+		},
+		{
+			name:       "value flag takes the next argument",
+			args:       []string{"bash/cat#list", "--block", "2"},
+			positional: []string{"bash/cat#list"},
+			flags:      map[string]string{"block": "2"},
+		},
+		{
+			name:       "inline value",
+			args:       []string{"--block=2", "bash/cat"},
+			positional: []string{"bash/cat"},
+			flags:      map[string]string{"block": "2"},
+		},
+		{
+			name:       "boolean flag keeps the next argument",
+			args:       []string{"--print", "bash/cat"},
+			positional: []string{"bash/cat"},
+			flags:      map[string]string{"print": ""},
+		},
+		{
+			name:       "value flag at the end",
+			args:       []string{"bash/cat", "--block"},
+			positional: []string{"bash/cat"},
+			flags:      map[string]string{"block": ""},
+		},
+		{
+			name:       "double dash is positional",
+			args:       []string{"--", "-v"},
+			positional: []string{"--", "-v"},
+			flags:      map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := parseArgs(tt.args, "block")
+			if !slices.Equal(parsed.positional, tt.positional) {
+				t.Errorf("parseArgs(%q) positional = %q, want %q", tt.args, parsed.positional, tt.positional)
+			}
+			if !maps.Equal(parsed.flags, tt.flags) {
+				t.Errorf("parseArgs(%q) flags = %v, want %v", tt.args, parsed.flags, tt.flags)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	bkey "github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// blockLines is the range of rendered lines of a code block in the content
+// view, from the top border to the bottom border.
+type blockLines struct {
+	start int
+	end   int
+}
+
+// blockCursor moves between the code blocks of the content pane, copyable or
+// not.
+type blockCursor struct {
+	// the rendered content of the section without the cursor.
+	content string
+	// the rendered lines of the code blocks, by code block index.
+	lines  []blockLines
+	index  int
+	active bool
+}
+
+// findBlockLines returns the rendered lines of the code blocks of the
+// section, by code block index. They are found by the border placeholders
+// before they are replaced, the indented code blocks are skipped.
+func findBlockLines(content string, config Config, section Section) []blockLines {
+	var rendered []blockLines
+	for i, line := range strings.Split(content, "\n") {
+		switch {
+		case strings.Contains(line, config.CodeBlockPrefixTemp):
+			rendered = append(rendered, blockLines{start: i, end: i})
+		case strings.Contains(line, config.CodeBlockSuffixTemp) && len(rendered) > 0:
+			rendered[len(rendered)-1].end = i
+		}
+	}
+
+	var lines []blockLines
+	for r, i := range section.renderedBlocks() {
+		if r < len(rendered) && i >= 0 && i < len(section.CodeBlocks) {
+			lines = append(lines, rendered[r])
+		}
+	}
+	return lines
+}
+
+// toggleBlockCursor starts the block cursor at the first code block in view,
+// or stops it.
+func (m *Model) toggleBlockCursor() tea.Cmd {
+	if m.blocks.active {
+		m.stopBlockCursor()
+		return nil
+	}
+	if m.run != nil && m.run.visible {
+		return nil
+	}
+	if len(m.blocks.lines) == 0 {
+		return m.showError(fmt.Errorf("section %q has no code blocks", m.selectedSection().Title))
+	}
+
+	m.blocks.active = true
+	m.blocks.index = len(m.blocks.lines) - 1
+	for i, lines := range m.blocks.lines {
+		if lines.start >= m.Code.YOffset {
+			m.blocks.index = i
+			break
+		}
+	}
+	m.moveBlockCursor(0)
+	return nil
+}
+
+// stopBlockCursor hides the block cursor.
+func (m *Model) stopBlockCursor() {
+	m.blocks.active = false
+	m.renderContent()
+}
+
+// updateBlockCursor handles the keys of the block cursor, it reports false
+// for the keys it doesn't handle.
+func (m *Model) updateBlockCursor(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case msg.String() == "down" || msg.String() == "j" || msg.String() == "ctrl+n":
+		m.moveBlockCursor(1)
+	case msg.String() == "up" || msg.String() == "k" || msg.String() == "ctrl+p":
+		m.moveBlockCursor(-1)
+	case msg.String() == "enter":
+		codeBlocks := m.selectedSection().CodeBlocks
+		if m.blocks.index >= len(codeBlocks) {
+			return nil, true
+		}
+		return m.copyCodeBlock(codeBlocks[m.blocks.index], m.config.ExitAfterCopy), true
+	case msg.String() == "esc" || bkey.Matches(msg, m.keys.BlockCursor):
+		m.stopBlockCursor()
+	case bkey.Matches(msg, m.keys.NextPane, m.keys.PrevPane):
+		m.stopBlockCursor()
+		return nil, false
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// moveBlockCursor moves the cursor by delta code blocks and scrolls the
+// content to keep the code block in view.
+func (m *Model) moveBlockCursor(delta int) {
+	m.blocks.index = max(0, min(m.blocks.index+delta, len(m.blocks.lines)-1))
+	m.renderContent()
+	m.scrollContent(m.blocks.lines[m.blocks.index])
+}
+
+// scrollContent scrolls the content and the line numbers the least to show
+// the lines.
+func (m *Model) scrollContent(lines blockLines) {
+	offset := m.Code.YOffset
+	if lines.end >= offset+m.Code.Height {
+		offset = lines.end - m.Code.Height + 1
+	}
+	if lines.start < offset {
+		offset = lines.start
+	}
+	m.Code.SetYOffset(offset)
+	m.LineNumbers.SetYOffset(offset)
+}
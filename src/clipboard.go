@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+const (
+	clipboardAuto    = "auto"
+	clipboardSystem  = "system"
+	clipboardOSC52   = "osc52"
+	clipboardTmux    = "tmux"
+	clipboardCommand = "command"
+	clipboardStdout  = "stdout"
+)
+
+// clipboardWaitDelay is how long to wait for the output of a clipboard
+// command after it has exited.
+const clipboardWaitDelay = 500 * time.Millisecond
+
+// copyToClipboard writes the content with the clipboard backend of the
+// config.
+func copyToClipboard(config Config, content string) error {
+	switch config.ClipboardBackend {
+	case clipboardAuto, "":
+		return copyAuto(config, content)
+	case clipboardSystem:
+		return clipboard.WriteAll(content)
+	case clipboardOSC52:
+		return copyOSC52(content)
+	case clipboardTmux:
+		return copyTmux(content)
+	case clipboardCommand:
+		return copyCommand(config.ClipboardCommand, content)
+	case clipboardStdout:
+		_, err := fmt.Println(content)
+		return err
+	}
+	return fmt.Errorf("unknown clipboard_backend %q, use auto, system, osc52, tmux, command or stdout", config.ClipboardBackend)
+}
+
+// copyAuto tries the backends of autoBackends in turn, and falls back to
+// OSC 52 if there is a terminal to write it to.
+func copyAuto(config Config, content string) error {
+	for _, backend := range autoBackends(config) {
+		config.ClipboardBackend = backend
+		if copyToClipboard(config, content) == nil {
+			return nil
+		}
+	}
+	if !hasTerminal() {
+		return errors.New("no clipboard is available, set clipboard_backend or clipboard_command")
+	}
+	return copyOSC52(content)
+}
+
+// autoBackends returns the backends the auto backend tries: the clipboard
+// command, the system clipboard and tmux. The system clipboard is skipped
+// over SSH, it is the clipboard of the remote machine.
+func autoBackends(config Config) []string {
+	var backends []string
+	if config.ClipboardCommand != "" {
+		backends = append(backends, clipboardCommand)
+	}
+	if !isSSH() {
+		backends = append(backends, clipboardSystem)
+	}
+	if os.Getenv("TMUX") != "" {
+		backends = append(backends, clipboardTmux)
+	}
+	return backends
+}
+
+// hasTerminal reports whether a terminal can receive the OSC 52 sequence,
+// without one the sequence is written but nothing is copied.
+func hasTerminal() bool {
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		tty.Close()
+		return true
+	}
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// isSSH reports whether mdf runs in an SSH session.
+func isSSH() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// copyOSC52 asks the terminal to set its clipboard with the OSC 52 escape
+// sequence, it works over SSH if the terminal supports it.
+func copyOSC52(content string) error {
+	seq := osc52.New(content)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if os.Getenv("STY") != "" {
+		seq = seq.Screen()
+	}
+
+	var out io.Writer = os.Stderr
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		out = tty
+	}
+	if _, err := seq.WriteTo(out); err != nil {
+		return fmt.Errorf("failed to write OSC 52 sequence: %w", err)
+	}
+	return nil
+}
+
+// copyTmux loads the content into the tmux paste buffer, -w also sets the
+// clipboard of the terminal.
+func copyTmux(content string) error {
+	return runClipboardCommand([]string{"tmux", "load-buffer", "-w", "-"}, content)
+}
+
+// copyCommand pipes the content into the clipboard command, such as wl-copy
+// or xclip -selection clipboard.
+func copyCommand(command, content string) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("clipboard_command is not set")
+	}
+	return runClipboardCommand(args, content)
+}
+
+// runClipboardCommand pipes the content into the command. xclip and wl-copy
+// fork a child that keeps the clipboard and inherits stderr, WaitDelay stops
+// waiting for it once the command itself has exited.
+func runClipboardCommand(args []string, content string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = clipboardWaitDelay
+	if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestAutoBackends(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		command string
+		want    []string
+	}{
+		{"local", nil, "", []string{clipboardSystem}},
+		{"command first", nil, "wl-copy", []string{clipboardCommand, clipboardSystem}},
+		{"ssh connection", map[string]string{"SSH_CONNECTION": "10.0.0.1 52000 10.0.0.2 22"}, "", nil},
+		{"ssh tty", map[string]string{"SSH_TTY": "/dev/pts/1"}, "", nil},
+		{"tmux", map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"}, "", []string{clipboardSystem, clipboardTmux}},
+		{
+			name:    "tmux over ssh",
+			env:     map[string]string{"SSH_TTY": "/dev/pts/1", "TMUX": "/tmp/tmux-1000/default,1,0"},
+			command: "xclip -selection clipboard",
+			want:    []string{clipboardCommand, clipboardTmux},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"SSH_CONNECTION", "SSH_TTY", "TMUX"} {
+				t.Setenv(key, tt.env[key])
+			}
+			got := autoBackends(Config{ClipboardCommand: tt.command})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("autoBackends = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyCommand(t *testing.T) {
+	if _, err := exec.LookPath("tee"); err != nil {
+		t.Skip("tee is not installed")
+	}
+	file := filepath.Join(t.TempDir(), "clipboard")
+	if err := copyCommand("tee "+file, "kubectl get pods"); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(file); err != nil || string(content) != "kubectl get pods" {
+		t.Errorf("copied content = %q, %v, want kubectl get pods", content, err)
+	}
+
+	// the auto backend tries the command first
+	t.Setenv("SSH_TTY", "/dev/pts/1")
+	t.Setenv("TMUX", "")
+	if err := copyToClipboard(Config{ClipboardBackend: clipboardAuto, ClipboardCommand: "tee " + file}, "kubectl get nodes"); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(file); err != nil || string(content) != "kubectl get nodes" {
+		t.Errorf("auto copied content = %q, %v, want kubectl get nodes", content, err)
+	}
+
+	if err := copyCommand("", "pods"); err == nil {
+		t.Errorf("copyCommand without a command returned no error")
+	}
+	err := copyCommand("tee "+filepath.Join(file, "missing"), "pods")
+	if err == nil || !strings.Contains(err.Error(), "tee: ") {
+		t.Errorf("copyCommand of a failing command = %v, want the stderr of tee", err)
+	}
+}
+
+func TestCopyToClipboardUnknown(t *testing.T) {
+	if err := copyToClipboard(Config{ClipboardBackend: "xsel"}, "pods"); err == nil {
+		t.Errorf("copyToClipboard with an unknown backend returned no error")
+	}
+}
@@ -28,13 +28,33 @@ https://github.com/kugarocks/markdown-finder
 
 Usage:
   mdf                   - for interactive mode (3 panes)
-  mdf example           - fuzzy find snippet (2 panes)
-  mdf get repo <name>   - get repo from github
+  mdf example           - fuzzy find snippet (2 panes), <snippet>#<section> selects the section
+  mdf open <link>       - open mdf://<repo>/<folder>/<file>#<section>
+  mdf copy <query>      - copy snippet, <snippet>#<section> [--block N] [--print]
+  mdf search <terms>    - search section titles, prose and code blocks
+  mdf search            - search interactively
+  mdf history           - list the copy history, add <n> to copy entry n again [--print]
+  mdf history clear     - clear the copy history
+  mdf new <path>        - create snippet <folder>/<name> [--template <name>]
+  mdf rm <path>         - delete snippet <folder>/<file>
+  mdf mv <from> <to>    - rename snippet to <folder>/<name>
+  mdf get repo <url>    - get repo from github <user>/<repo> or any git URL [--name <name>]
+  mdf repo add <path>   - register an existing directory as repo [--name <name>]
+  mdf repo update       - pull the current repo, <name> or --all
+  mdf repo remove       - remove repo <name>, add --delete to delete its directory
+  mdf repo status       - show ahead/behind and changed files of every repo
   mdf set repo          - switch repo
   mdf set folder        - switch folder
   mdf list repo         - list all repos
   mdf list folder       - list all folders
   mdf list snippet      - list all snippets
+  mdf list section      - list all sections, optionally of <snippet>[#<section>]
+  mdf list block        - list all code blocks, optionally of <snippet>[#<section>]
+  mdf list tag          - list all tags with the number of snippets
+                          add --tag <tag> to list the snippets with the tag only
+                          add --json or --format tsv for machine-readable output
+
+  Add --all to any command to use the snippets of every repo, e.g. mdf --all example
 
 `
 	DefaultSnippetConfig = `{
@@ -134,18 +154,32 @@ MDF_HOME=/Users/kuga/mdf \
 // file name of the metadata.
 type Config struct {
 	Home              string `yaml:"-"`
+	AllRepos          bool   `yaml:"-"`
 	RepoName          string `env:"MDF_REPO_NAME" yaml:"repo_name"`
 	FolderName        string `env:"MDF_FOLDER_NAME" yaml:"folder_name"`
 	RepoConfigFile    string `env:"MDF_REPO_CONFIG_FILE" yaml:"repo_config_file"`
 	SnippetConfigFile string `env:"MDF_SNIPPET_CONFIG_FILE" yaml:"snippet_config_file"`
 
+	// Scan
+	IncludeExtensions []string `env:"MDF_INCLUDE_EXTENSIONS" envSeparator:"," yaml:"include_extensions"`
+	SectionSplit      string   `env:"MDF_SECTION_SPLIT" yaml:"section_split"`
+
 	// Pane
 	DefaultPane           string `env:"MDF_DEFAULT_PANE" yaml:"default_pane"`
 	AlwaysShowSnippetPane bool   `env:"MDF_ALWAYS_SHOW_SNIPPET_PANE" yaml:"always_show_snippet_pane"`
 	ExitAfterCopy         bool   `env:"MDF_EXIT_AFTER_COPY" yaml:"exit_after_copy"`
 
+	// History
+	HistoryMaxEntries int `env:"MDF_HISTORY_MAX_ENTRIES" yaml:"history_max_entries"`
+	HistoryMaxDays    int `env:"MDF_HISTORY_MAX_DAYS" yaml:"history_max_days"`
+
+	// Clipboard
+	ClipboardBackend string `env:"MDF_CLIPBOARD_BACKEND" yaml:"clipboard_backend"`
+	ClipboardCommand string `env:"MDF_CLIPBOARD_COMMAND" yaml:"clipboard_command"`
+
 	// Layout
 	BaseMarginTop         int `env:"MDF_BASE_MARGIN_TOP" yaml:"base_margin_top"`
+	FolderTitleBarWidth   int `env:"MDF_FOLDER_TITLE_BAR_WIDTH" yaml:"folder_title_bar_width"`
 	SnippetTitleBarWidth  int `env:"MDF_SNIPPET_TITLE_BAR_WIDTH" yaml:"snippet_title_bar_width"`
 	SectionTitleBarWidth  int `env:"MDF_SECTION_TITLE_BAR_WIDTH" yaml:"section_title_bar_width"`
 	ContentTitleBarWidth  int `env:"MDF_CONTENT_TITLE_BAR_WIDTH" yaml:"content_title_bar_width"`
@@ -161,6 +195,8 @@ type Config struct {
 	CopiedBarBgColor         string `env:"MDF_COPIED_BAR_BG_COLOR" yaml:"copied_bar_bg_color"`
 	CopiedBarFgColor         string `env:"MDF_COPIED_BAR_FG_COLOR" yaml:"copied_bar_fg_color"`
 	CopiedItemFgColor        string `env:"MDF_COPIED_ITEM_FG_COLOR" yaml:"copied_item_fg_color"`
+	ErrorBarBgColor          string `env:"MDF_ERROR_BAR_BG_COLOR" yaml:"error_bar_bg_color"`
+	ErrorBarFgColor          string `env:"MDF_ERROR_BAR_FG_COLOR" yaml:"error_bar_fg_color"`
 	ContentLineNumberFgColor string `env:"MDF_CONTENT_LINE_NUMBER_FG_COLOR" yaml:"content_line_number_fg_color"`
 
 	// Code Block
@@ -168,6 +204,7 @@ type Config struct {
 	CodeBlockBorderPadding string `env:"MDF_CODE_BLOCK_BORDER_PADDING" yaml:"code_block_border_padding"`
 	CodeBlockBorderLength  int    `env:"MDF_CODE_BLOCK_BORDER_LENGTH" yaml:"code_block_border_length"`
 	CodeBlockTitleCopy     string `env:"MDF_CODE_BLOCK_TITLE_COPY" yaml:"code_block_title_copy"`
+	HintAlphabet           string `env:"MDF_HINT_ALPHABET" yaml:"hint_alphabet"`
 	CodeBlockPrefixTemp    string `yaml:"-"`
 	CodeBlockSuffixTemp    string `yaml:"-"`
 	CodeBlockBorderDefault string `yaml:"-"`
@@ -176,9 +213,24 @@ type Config struct {
 	CopyContentKeys        []string `env:"MDF_COPY_CONTENT_KEYS" envSeparator:"," yaml:"copy_content_keys"`
 	CopyContentKeysCapital []string `yaml:"-"`
 	EditSnippetKeys        []string `env:"MDF_EDIT_SNIPPET_KEYS" envSeparator:"," yaml:"edit_snippet_keys"`
+	RunBlockKeys           []string `env:"MDF_RUN_BLOCK_KEYS" envSeparator:"," yaml:"run_block_keys"`
+	NewSnippetKeys         []string `env:"MDF_NEW_SNIPPET_KEYS" envSeparator:"," yaml:"new_snippet_keys"`
+	RenameSnippetKeys      []string `env:"MDF_RENAME_SNIPPET_KEYS" envSeparator:"," yaml:"rename_snippet_keys"`
+	DeleteSnippetKeys      []string `env:"MDF_DELETE_SNIPPET_KEYS" envSeparator:"," yaml:"delete_snippet_keys"`
+	PickTagKeys            []string `env:"MDF_PICK_TAG_KEYS" envSeparator:"," yaml:"pick_tag_keys"`
+	SortFrecentKeys        []string `env:"MDF_SORT_FRECENT_KEYS" envSeparator:"," yaml:"sort_frecent_keys"`
+	SwitchRepoKeys         []string `env:"MDF_SWITCH_REPO_KEYS" envSeparator:"," yaml:"switch_repo_keys"`
+	CopyHistoryKeys        []string `env:"MDF_COPY_HISTORY_KEYS" envSeparator:"," yaml:"copy_history_keys"`
+	BlockCursorKeys        []string `env:"MDF_BLOCK_CURSOR_KEYS" envSeparator:"," yaml:"block_cursor_keys"`
+	FindKeys               []string `env:"MDF_FIND_KEYS" envSeparator:"," yaml:"find_keys"`
+	HintKeys               []string `env:"MDF_HINT_KEYS" envSeparator:"," yaml:"hint_keys"`
+	CopyLinkKeys           []string `env:"MDF_COPY_LINK_KEYS" envSeparator:"," yaml:"copy_link_keys"`
 	NextPaneKeys           []string `env:"MDF_NEXT_PANE_KEYS" envSeparator:"," yaml:"next_pane_keys"`
 	PrevPaneKeys           []string `env:"MDF_PREV_PANE_KEYS" envSeparator:"," yaml:"prev_pane_keys"`
 	ToggleSnippetPaneKeys  []string `env:"MDF_TOGGLE_SNIPPET_PANE_KEYS" envSeparator:"," yaml:"toggle_snippet_pane_keys"`
+
+	// repoPaths are the directories of the repos outside of the repo base.
+	repoPaths map[string]string
 }
 
 func newConfig() Config {
@@ -188,13 +240,26 @@ func newConfig() Config {
 		RepoConfigFile:    "repo-config.json",
 		SnippetConfigFile: "snippet-config.json",
 
+		// Scan
+		IncludeExtensions: []string{},
+		SectionSplit:      sectionSplitHR,
+
 		// Pane
 		DefaultPane:           "section",
 		AlwaysShowSnippetPane: false,
 		ExitAfterCopy:         false,
 
+		// History
+		HistoryMaxEntries: 500,
+		HistoryMaxDays:    90,
+
+		// Clipboard
+		ClipboardBackend: clipboardAuto,
+		ClipboardCommand: "",
+
 		// Layout
 		BaseMarginTop:         1,
+		FolderTitleBarWidth:   20,
 		SnippetTitleBarWidth:  33,
 		SectionTitleBarWidth:  33,
 		ContentTitleBarWidth:  86,
@@ -212,6 +277,8 @@ func newConfig() Config {
 		CopiedBarBgColor:         "42",
 		CopiedBarFgColor:         "238",
 		CopiedItemFgColor:        "42",
+		ErrorBarBgColor:          "160",
+		ErrorBarFgColor:          "255",
 		ContentLineNumberFgColor: "241",
 
 		// Code Block
@@ -219,12 +286,25 @@ func newConfig() Config {
 		CodeBlockBorderPadding: "-",
 		CodeBlockBorderLength:  CodeBlockBorderLength,
 		CodeBlockTitleCopy:     "Press {key} to copy",
+		HintAlphabet:           defaultHintAlphabet,
 		CodeBlockPrefixTemp:    "------------------BEG------------------",
 		CodeBlockSuffixTemp:    "------------------END------------------",
 
 		// keys
 		CopyContentKeys:       []string{"c", "d", "e", "f", "g"},
 		EditSnippetKeys:       []string{"i"},
+		RunBlockKeys:          []string{"x"},
+		NewSnippetKeys:        []string{"a"},
+		RenameSnippetKeys:     []string{"r"},
+		DeleteSnippetKeys:     []string{"X", "delete"},
+		PickTagKeys:           []string{"t"},
+		SortFrecentKeys:       []string{"o"},
+		SwitchRepoKeys:        []string{"R"},
+		CopyHistoryKeys:       []string{"H"},
+		BlockCursorKeys:       []string{"v"},
+		FindKeys:              []string{"ctrl+f"},
+		HintKeys:              []string{";"},
+		CopyLinkKeys:          []string{"y"},
 		NextPaneKeys:          []string{"n", "tab", "right"},
 		PrevPaneKeys:          []string{"N", "shift+tab", "left"},
 		ToggleSnippetPaneKeys: []string{"s", "p"},
@@ -280,6 +360,7 @@ func readConfig() Config {
 	if err = env.Parse(&config); err != nil {
 		config = newConfig()
 	}
+	config.repoPaths = readRepoPaths(config)
 
 	// set code block default config
 	if config.CodeBlockBorderLength <= 0 {
@@ -319,8 +400,21 @@ func (config Config) writeConfig() error {
 
 	// Set flow style for array fields
 	setFlowStyle(&node, map[string]struct{}{
+		"include_extensions":       {},
 		"copy_content_keys":        {},
 		"edit_snippet_keys":        {},
+		"run_block_keys":           {},
+		"new_snippet_keys":         {},
+		"rename_snippet_keys":      {},
+		"delete_snippet_keys":      {},
+		"pick_tag_keys":            {},
+		"sort_frecent_keys":        {},
+		"switch_repo_keys":         {},
+		"copy_history_keys":        {},
+		"block_cursor_keys":        {},
+		"find_keys":                {},
+		"hint_keys":                {},
+		"copy_link_keys":           {},
 		"next_pane_keys":           {},
 		"prev_pane_keys":           {},
 		"toggle_snippet_pane_keys": {},
@@ -362,10 +456,47 @@ func (config Config) getRepoBase() string {
 
 // getRepoPath returns the full path for the configured repo name
 func (config Config) getRepoPath() string {
-	parts := strings.Split(config.RepoName, "/")
+	if repoPath, ok := config.repoPaths[config.getRepoName()]; ok {
+		return repoPath
+	}
+	parts := strings.Split(config.getRepoName(), "/")
 	return filepath.Join(append([]string{config.getRepoBase()}, parts...)...)
 }
 
+// getRepoName returns the configured repo name, new snippets are created in
+// the default repo when the All entry is chosen.
+func (config Config) getRepoName() string {
+	if config.RepoName == allReposName {
+		return defaultRepoName
+	}
+	return config.RepoName
+}
+
+// isAllRepos reports whether the snippets of every repo are loaded.
+func (config Config) isAllRepos() bool {
+	return config.AllRepos || config.RepoName == allReposName
+}
+
+// forRepo returns the configuration of the named repo.
+func (config Config) forRepo(name string) Config {
+	config.RepoName = name
+	config.AllRepos = false
+	return config
+}
+
+// getSnippetRepoPath returns the full path of the repo the snippet belongs to.
+func (config Config) getSnippetRepoPath(snippet Snippet) string {
+	if snippet.Repo == "" {
+		return config.getRepoPath()
+	}
+	return config.forRepo(snippet.Repo).getRepoPath()
+}
+
+// getSnippetPath returns the full path of the snippet file.
+func (config Config) getSnippetPath(snippet Snippet) string {
+	return filepath.Join(config.getSnippetRepoPath(snippet), snippet.Path())
+}
+
 // getDefaultRepoPath returns the full path for the default repo name
 func (config Config) getDefaultRepoPath() string {
 	parts := strings.Split(defaultRepoName, "/")
@@ -394,6 +525,18 @@ func (config Config) newKeyMap() KeyMap {
 	setKeyBinding(&km.CopyContent, config.CopyContentKeys, "copy")
 	setKeyBinding(&km.CopyContentExit, config.CopyContentKeysCapital, "copy & exit")
 	setKeyBinding(&km.EditSnippet, config.EditSnippetKeys, "edit")
+	setKeyBinding(&km.RunBlock, config.RunBlockKeys, "run")
+	setKeyBinding(&km.NewSnippet, config.NewSnippetKeys, "new snippet")
+	setKeyBinding(&km.RenameSnippet, config.RenameSnippetKeys, "rename snippet")
+	setKeyBinding(&km.DeleteSnippet, config.DeleteSnippetKeys, "delete snippet")
+	setKeyBinding(&km.PickTag, config.PickTagKeys, "filter by tag")
+	setKeyBinding(&km.SortFrecent, config.SortFrecentKeys, "frecent sort")
+	setKeyBinding(&km.SwitchRepo, config.SwitchRepoKeys, "switch repo")
+	setKeyBinding(&km.CopyHistory, config.CopyHistoryKeys, "copy history")
+	setKeyBinding(&km.BlockCursor, config.BlockCursorKeys, "block cursor")
+	setKeyBinding(&km.Find, config.FindKeys, "find in content")
+	setKeyBinding(&km.Hint, config.HintKeys, "copy by hint")
+	setKeyBinding(&km.CopyLink, config.CopyLinkKeys, "copy link")
 	setKeyBinding(&km.NextPane, config.NextPaneKeys, "next")
 	setKeyBinding(&km.PrevPane, config.PrevPaneKeys, "prev")
 	setKeyBinding(&km.ToggleSnippetPane, config.ToggleSnippetPaneKeys, "toggle snippet")
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sahilm/fuzzy"
+)
+
+const copyUsage = "Usage: mdf copy <snippet>[#<section>] [--block N] [--print]"
+
+// runCopy resolves a snippet, section and code block without starting the
+// interactive mode, then copies the content to the clipboard or prints it.
+//
+//	mdf copy bash/cat                - copy the whole snippet file
+//	mdf copy bash/cat#list           - copy the first copyable block of the section
+//	mdf copy bash/cat#list --block 2 - copy the second code block of the section
+func runCopy(config Config, snippets []Snippet, args []string) error {
+	parsed := parseArgs(args, "block")
+	query := parsed.arg(0)
+	if query == "" {
+		return errors.New(copyUsage)
+	}
+
+	target, err := resolveCopyTarget(config, snippets, query, parsed.get("block"))
+	if err != nil {
+		return err
+	}
+
+	if parsed.has("print") {
+		fmt.Println(target.Content)
+	} else if err = copyToClipboard(config, target.Content); err != nil {
+		return fmt.Errorf("failed to write clipboard: %w", err)
+	}
+	recordSnippetUsage(config, snippets, target.Snippet, target.Slug, true)
+	return appendHistory(config, newHistoryEntry(config, target))
+}
+
+// copyTarget is the content addressed by a copy query, and where it comes
+// from. Section and Slug are empty for the whole snippet file, Block is the
+// 1-based code block index or 0.
+type copyTarget struct {
+	Snippet Snippet
+	Section string
+	Slug    string
+	Block   int
+	Content string
+}
+
+// resolveCopyTarget returns the content addressed by the query and block
+// number. Without a section and block the whole snippet file is returned.
+func resolveCopyTarget(config Config, snippets []Snippet, query, block string) (copyTarget, error) {
+	snippetQuery, sectionQuery := splitTarget(query)
+	snippet := findSnippet(snippetQuery, snippets)
+	if snippet.File == "" {
+		return copyTarget{}, fmt.Errorf("no snippet matches %q", snippetQuery)
+	}
+	target := copyTarget{Snippet: snippet}
+
+	if sectionQuery == "" && block == "" {
+		content, err := os.ReadFile(config.getSnippetPath(snippet))
+		if err != nil {
+			return copyTarget{}, fmt.Errorf("failed to read snippet: %w", err)
+		}
+		target.Content = string(content)
+		return target, nil
+	}
+
+	sections, err := readSections(config, snippet)
+	if err != nil {
+		return copyTarget{}, fmt.Errorf("failed to read snippet: %w", err)
+	}
+	if len(sections) == 0 {
+		return copyTarget{}, fmt.Errorf("snippet %s has no sections", snippet.Path())
+	}
+
+	section := sections[0]
+	if sectionQuery != "" {
+		var ok bool
+		section, ok = findSection(sectionQuery, snippet, sections)
+		if !ok {
+			return copyTarget{}, fmt.Errorf("no section of %s matches %q", snippet.Path(), sectionQuery)
+		}
+	}
+	target.Section, target.Slug = section.Title, section.Slug
+
+	if block == "" {
+		codeBlock := defaultCodeBlock(section)
+		target.Block = codeBlock.Index
+		target.Content = codeBlock.Content
+		return target, nil
+	}
+
+	n, err := strconv.Atoi(block)
+	if err != nil || n < 1 || n > len(section.CodeBlocks) {
+		return copyTarget{}, fmt.Errorf("section %q has %d code blocks, invalid block %q", section.Title, len(section.CodeBlocks), block)
+	}
+	target.Block = n
+	target.Content = section.CodeBlocks[n-1].Content
+	return target, nil
+}
+
+// splitTarget splits a <snippet>#<section> query.
+func splitTarget(query string) (snippetQuery, sectionQuery string) {
+	snippetQuery, sectionQuery, _ = strings.Cut(query, "#")
+	return snippetQuery, sectionQuery
+}
+
+// findSection finds the section of the snippet by its slug, or fuzzy finds
+// it by its title, frecent sections rank higher.
+func findSection(search string, snippet Snippet, sections []Section) (Section, bool) {
+	for _, section := range sections {
+		if section.Slug == search {
+			return section, true
+		}
+	}
+
+	titles := make([]string, len(sections))
+	for i, section := range sections {
+		titles[i] = section.Title
+	}
+	matches := fuzzy.Find(search, titles)
+	if len(matches) == 0 {
+		return Section{}, false
+	}
+	now := time.Now()
+	return sections[bestMatch(matches, func(i int) float64 {
+		return snippet.SectionUsage[sections[i].Slug].frecency(now)
+	})], true
+}
+
+// defaultCodeBlock returns the first copyable code block of the section,
+// falling back to the first code block and then the section content.
+func defaultCodeBlock(section Section) CodeBlock {
+	for _, codeBlock := range section.CodeBlocks {
+		if _, copyable := codeBlock.Meta[metaKeyCopyable]; copyable {
+			return codeBlock
+		}
+	}
+	if len(section.CodeBlocks) > 0 {
+		return section.CodeBlocks[0]
+	}
+	return CodeBlock{Content: section.Content}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	bkey "github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// the SGR sequences of a match, reverse video doesn't change the colors of
+// the rendered content. The current match is underlined as well.
+const (
+	findMatchStart   = "\x1b[7m"
+	findMatchEnd     = "\x1b[27m"
+	findCurrentStart = "\x1b[7;4m"
+	findCurrentEnd   = "\x1b[27;24m"
+)
+
+// findMatch is a match in the content view, start and end are the rune
+// offsets in the line without escape sequences.
+type findMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// contentFind finds the text of the query in the content pane.
+type contentFind struct {
+	input textinput.Model
+	// whether the query is being typed.
+	typing  bool
+	query   string
+	matches []findMatch
+	index   int
+}
+
+// active reports whether the matches are highlighted.
+func (f contentFind) active() bool {
+	return f.typing || f.query != ""
+}
+
+// findMatches returns the case-insensitive matches of the query in the
+// rendered content.
+func findMatches(content, query string) []findMatch {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return nil
+	}
+
+	var matches []findMatch
+	for i, line := range strings.Split(content, "\n") {
+		text := []rune(ansi.Strip(line))
+		for j := range text {
+			text[j] = unicode.ToLower(text[j])
+		}
+		for j := 0; j+len(q) <= len(text); {
+			if string(text[j:j+len(q)]) != string(q) {
+				j++
+				continue
+			}
+			matches = append(matches, findMatch{line: i, start: j, end: j + len(q)})
+			j += len(q)
+		}
+	}
+	return matches
+}
+
+// highlightMatches highlights the matches in the lines of the content. The
+// highlight is set again after every escape sequence of a match, so the
+// styles of the content are kept.
+func (f contentFind) highlightMatches(lines []string) {
+	for i := 0; i < len(f.matches); {
+		j := i
+		for j < len(f.matches) && f.matches[j].line == f.matches[i].line {
+			j++
+		}
+		if line := f.matches[i].line; line < len(lines) {
+			lines[line] = f.highlightLine(lines[line], i, j)
+		}
+		i = j
+	}
+}
+
+// highlightLine highlights the matches from..to of the line.
+func (f contentFind) highlightLine(line string, from, to int) string {
+	var b strings.Builder
+	pos, m := 0, from
+	start, end := "", ""
+	for i := 0; i < len(line); {
+		if start != "" && pos == f.matches[m].end {
+			b.WriteString(end)
+			start, end = "", ""
+			m++
+		}
+		if line[i] == '\x1b' {
+			n := escapeLen(line[i:])
+			b.WriteString(line[i : i+n])
+			b.WriteString(start)
+			i += n
+			continue
+		}
+		if start == "" && m < to && pos == f.matches[m].start {
+			start, end = findMatchStart, findMatchEnd
+			if m == f.index {
+				start, end = findCurrentStart, findCurrentEnd
+			}
+			b.WriteString(start)
+		}
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		pos++
+	}
+	b.WriteString(end)
+	return b.String()
+}
+
+// escapeLen returns the length of the escape sequence at the start of s.
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
+
+// startFind opens the find input with the last query.
+func (m *Model) startFind() tea.Cmd {
+	if m.run != nil && m.run.visible {
+		return nil
+	}
+	input := textinput.New()
+	input.Prompt = "Find: "
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(m.find.query)
+	input.Focus()
+	m.find.input = input
+	m.find.typing = true
+	return nil
+}
+
+// stopFind removes the query and the highlights.
+func (m *Model) stopFind() {
+	m.find = contentFind{}
+	m.renderContent()
+}
+
+// setFindQuery finds the query in the content, the current match is the
+// first one in view.
+func (m *Model) setFindQuery(query string) {
+	m.find.query = query
+	m.find.matches = findMatches(m.blocks.content, query)
+	m.find.index = 0
+	for i, match := range m.find.matches {
+		if match.line >= m.Code.YOffset {
+			m.find.index = i
+			break
+		}
+	}
+	m.moveFindMatch(0)
+}
+
+// updateFindInput handles the keys while the query is typed, the matches are
+// updated on every key.
+func (m *Model) updateFindInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.stopFind()
+		return nil
+	case tea.KeyEnter:
+		m.find.typing = false
+		query := m.find.query
+		if query != "" && len(m.find.matches) == 0 {
+			m.stopFind()
+			return m.showError(fmt.Errorf("no match for %q", query))
+		}
+		if query == "" {
+			m.stopFind()
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.find.input, cmd = m.find.input.Update(msg)
+	if query := m.find.input.Value(); query != m.find.query {
+		m.setFindQuery(query)
+	}
+	return cmd
+}
+
+// updateFind handles the keys of a find in the content pane, it reports false
+// for the keys it doesn't handle.
+func (m *Model) updateFind(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.run != nil && m.run.visible {
+		return nil, false
+	}
+	switch {
+	case msg.String() == "n":
+		m.moveFindMatch(1)
+	case msg.String() == "N":
+		m.moveFindMatch(-1)
+	case msg.String() == "esc":
+		m.stopFind()
+	case bkey.Matches(msg, m.keys.Find):
+		return m.startFind(), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// moveFindMatch moves to the next or the previous match, wrapping around,
+// and scrolls the content to keep it in view.
+func (m *Model) moveFindMatch(delta int) {
+	if len(m.find.matches) == 0 {
+		m.renderContent()
+		return
+	}
+	m.find.index = (m.find.index + delta + len(m.find.matches)) % len(m.find.matches)
+	m.renderContent()
+	line := m.find.matches[m.find.index].line
+	m.scrollContent(blockLines{start: line, end: line})
+}
+
+// findStatus returns the query and the match counter for the title bar.
+func (m *Model) findStatus() string {
+	status := "Find: " + m.find.query
+	if m.find.typing {
+		status = m.find.input.View()
+	}
+	if m.find.query == "" {
+		return status
+	}
+	if len(m.find.matches) == 0 {
+		return status + " (no matches)"
+	}
+	return fmt.Sprintf("%s (%d/%d)", status, m.find.index+1, len(m.find.matches))
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFindMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		query   string
+		want    []findMatch
+	}{
+		{
+			name:    "case insensitive",
+			content: "kubectl get pods\nGet the Pods",
+			query:   "pods",
+			want:    []findMatch{{line: 0, start: 12, end: 16}, {line: 1, start: 8, end: 12}},
+		},
+		{
+			name:    "matches don't overlap",
+			content: "aaaa a",
+			query:   "aa",
+			want:    []findMatch{{line: 0, start: 0, end: 2}, {line: 0, start: 2, end: 4}},
+		},
+		{
+			name:    "escape sequences are skipped",
+			content: "\x1b[1;38;5;42mkube\x1b[0mctl \x1b]8;;https://kubernetes.io\x1b\\get\x1b]8;;\x1b\\",
+			query:   "ectl get",
+			want:    []findMatch{{line: 0, start: 3, end: 11}},
+		},
+		{
+			name:    "rune offsets",
+			content: "├── café ☕ cafe",
+			query:   "CAF",
+			want:    []findMatch{{line: 0, start: 4, end: 7}, {line: 0, start: 11, end: 14}},
+		},
+		{
+			name:    "no match",
+			content: "kubectl get pods",
+			query:   "nodes",
+		},
+		{
+			name:    "empty query",
+			content: "kubectl get pods",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findMatches(tt.content, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findMatches(%q, %q) = %+v, want %+v", tt.content, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		query string
+		index int
+		want  string
+	}{
+		{
+			name:  "plain",
+			line:  "get pods and pods",
+			query: "pods",
+			index: 1,
+			want:  "get " + findMatchStart + "pods" + findMatchEnd + " and " + findCurrentStart + "pods" + findCurrentEnd,
+		},
+		{
+			name:  "highlight set again after a style",
+			line:  "\x1b[1mkube\x1b[0mctl",
+			query: "bect",
+			want:  "\x1b[1mku" + findCurrentStart + "be\x1b[0m" + findCurrentStart + "ct" + findCurrentEnd + "l",
+		},
+		{
+			name:  "match at the end of a styled line",
+			line:  "\x1b[32mpods\x1b[0m",
+			query: "pods",
+			want:  "\x1b[32m" + findCurrentStart + "pods" + findCurrentEnd + "\x1b[0m",
+		},
+		{
+			name:  "multibyte runes",
+			line:  "☕ café",
+			query: "café",
+			want:  "☕ " + findCurrentStart + "café" + findCurrentEnd,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := contentFind{query: tt.query, matches: findMatches(tt.line, tt.query), index: tt.index}
+			lines := []string{tt.line}
+			f.highlightMatches(lines)
+			if lines[0] != tt.want {
+				t.Errorf("highlightMatches(%q) = %q, want %q", tt.line, lines[0], tt.want)
+			}
+			if got, want := ansi.Strip(lines[0]), ansi.Strip(tt.line); got != want {
+				t.Errorf("highlighted text = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestEscapeLen(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"\x1b[0m", 4},
+		{"\x1b[1;38;5;42mpods", 12},
+		{"\x1b]8;;https://k8s.io\x07pods", 20},
+		{"\x1b]8;;https://k8s.io\x1b\\pods", 21},
+		{"\x1b7pods", 2},
+		{"\x1b[1", 3},
+		{"\x1b", 1},
+	}
+	for _, tt := range tests {
+		if got := escapeLen(tt.s); got != tt.want {
+			t.Errorf("escapeLen(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
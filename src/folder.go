@@ -3,10 +3,12 @@ package main
 import (
 	"fmt"
 	"io"
+	"path"
 	"strings"
 
 	"golang.org/x/exp/slices"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -29,6 +31,124 @@ func (f Folder) FilterValue() string {
 	return string(f)
 }
 
+// Name returns the last element of the folder path.
+func (f Folder) Name() string {
+	return path.Base(string(f))
+}
+
+// Depth returns the nesting level of the folder, top level folders are 0.
+func (f Folder) Depth() int {
+	return strings.Count(string(f), "/")
+}
+
+// compareFolders orders folder paths depth first, so a folder is followed by
+// its sub folders before its next sibling.
+func compareFolders(a, b string) int {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := strings.Compare(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+// folderTree is the folder hierarchy of the snippets. Parent folders without
+// snippets of their own are part of the tree, so it can be collapsed.
+type folderTree struct {
+	folders   []string
+	snippets  map[string]int
+	collapsed map[string]bool
+}
+
+func newFolderTree(snippets []Snippet) *folderTree {
+	t := &folderTree{
+		snippets:  make(map[string]int),
+		collapsed: make(map[string]bool),
+	}
+	seen := make(map[string]struct{})
+	for _, snippet := range snippets {
+		t.snippets[snippet.Folder]++
+		for folder := snippet.Folder; folder != "." && folder != ""; folder = path.Dir(folder) {
+			if _, ok := seen[folder]; ok {
+				break
+			}
+			seen[folder] = struct{}{}
+			t.folders = append(t.folders, folder)
+		}
+	}
+	slices.SortFunc(t.folders, compareFolders)
+	return t
+}
+
+// hasChildren reports whether the folder has sub folders.
+func (t *folderTree) hasChildren(folder string) bool {
+	i, found := slices.BinarySearchFunc(t.folders, folder, compareFolders)
+	return found && i+1 < len(t.folders) && strings.HasPrefix(t.folders[i+1], folder+"/")
+}
+
+// hidden reports whether a parent of the folder is collapsed.
+func (t *folderTree) hidden(folder string) bool {
+	for parent := path.Dir(folder); parent != "."; parent = path.Dir(parent) {
+		if t.collapsed[parent] {
+			return true
+		}
+	}
+	return false
+}
+
+// toggle collapses or expands the folder.
+func (t *folderTree) toggle(folder string) {
+	t.collapsed[folder] = !t.collapsed[folder]
+}
+
+// reveal expands the folder and its parents.
+func (t *folderTree) reveal(folder string) {
+	for ; folder != "."; folder = path.Dir(folder) {
+		delete(t.collapsed, folder)
+	}
+}
+
+// items returns the folders that aren't hidden by a collapsed parent.
+func (t *folderTree) items() []list.Item {
+	var items []list.Item
+	for _, folder := range t.folders {
+		if !t.hidden(folder) {
+			items = append(items, Folder(folder))
+		}
+	}
+	return items
+}
+
+// allItems returns every folder of the tree.
+func (t *folderTree) allItems() []list.Item {
+	items := make([]list.Item, 0, len(t.folders))
+	for _, folder := range t.folders {
+		items = append(items, Folder(folder))
+	}
+	return items
+}
+
+// label returns the indented folder name with the collapse marker.
+func (t *folderTree) label(f Folder) string {
+	marker := "  "
+	if t.hasChildren(string(f)) {
+		marker = "▾ "
+		if t.collapsed[string(f)] {
+			marker = "▸ "
+		}
+	}
+	return strings.Repeat("  ", f.Depth()) + marker + f.Name()
+}
+
+// saveFolderName saves the folder chosen in the folder pane, it is selected
+// on the next start.
+func saveFolderName(name string) error {
+	config := readConfig()
+	config.FolderName = name
+	return config.writeConfig()
+}
+
 // folderDelegate represents a folder list item.
 type folderDelegate struct{ styles FoldersBaseStyle }
 
@@ -42,8 +162,8 @@ func (d folderDelegate) Spacing() int {
 	return 0
 }
 
-// Update is what is called when the folder selection is updated.
-// TODO: Update the filter search for the snippets with the folder name.
+// Update is what is called when the folder selection is updated, the model
+// updates the snippet list.
 func (d folderDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
 	return nil
 }
@@ -55,15 +175,16 @@ func (d folderDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		return
 	}
 	_, _ = fmt.Fprint(w, "  ")
+	name := strings.Repeat("  ", f.Depth()) + f.Name()
 	if index == m.Index() {
-		_, _ = fmt.Fprint(w, d.styles.Selected.Render("→ "+string(f)))
+		_, _ = fmt.Fprint(w, d.styles.Selected.Render("→ "+name))
 		return
 	}
-	_, _ = fmt.Fprint(w, d.styles.Unselected.Render("  "+string(f)))
+	_, _ = fmt.Fprint(w, d.styles.Unselected.Render("  "+name))
 }
 
 // folderSelectDelegate represents a folder list item.
-type folderSelectDelegate struct{}
+type folderSelectDelegate struct{ tree *folderTree }
 
 func (d folderSelectDelegate) Height() int                             { return 1 }
 func (d folderSelectDelegate) Spacing() int                            { return 0 }
@@ -74,7 +195,11 @@ func (d folderSelectDelegate) Render(w io.Writer, m list.Model, index int, listI
 		return
 	}
 
-	str := fmt.Sprintf("%d. %s", index+1, string(i))
+	// the filtered folders are flat, show the full path
+	str := string(i)
+	if m.FilterState() == list.Unfiltered {
+		str = d.tree.label(i)
+	}
 
 	fn := folderItemStyle.Render
 	if index == m.Index() {
@@ -88,6 +213,7 @@ func (d folderSelectDelegate) Render(w io.Writer, m list.Model, index int, listI
 
 type folderSelectModel struct {
 	list    list.Model
+	tree    *folderTree
 	choice  string
 	config  *Config
 	quiting bool
@@ -98,30 +224,84 @@ func (m folderSelectModel) Init() tea.Cmd {
 }
 
 func (m folderSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	filterState := m.list.FilterState()
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if filterState == list.Filtering && msg.String() != "enter" {
+			break
+		}
+		folder, _ := m.list.SelectedItem().(Folder)
 		switch msg.String() {
 		case "q", "ctrl+c":
 			m.quiting = true
 			return m, tea.Quit
 		case "enter":
-			if i, ok := m.list.SelectedItem().(Folder); ok {
-				m.choice = string(i)
-				m.config.FolderName = string(i)
-				err := m.config.writeConfig()
-				if err != nil {
-					fmt.Printf("write config failed: %v\n", err)
-				}
+			if folder == "" {
+				return m, nil
+			}
+			// a parent folder without snippets can't be chosen, open it instead
+			if m.tree.snippets[string(folder)] == 0 {
+				m.tree.reveal(string(folder))
+				m.list.ResetFilter()
+				return m, m.refresh(folder)
+			}
+			m.choice = string(folder)
+			m.config.FolderName = string(folder)
+			err := m.config.writeConfig()
+			if err != nil {
+				fmt.Printf("write config failed: %v\n", err)
 			}
 			return m, tea.Quit
+		case " ", "right", "l":
+			if filterState == list.Unfiltered && m.tree.hasChildren(string(folder)) {
+				m.tree.toggle(string(folder))
+				return m, m.refresh(folder)
+			}
+			return m, nil
+		case "left", "h":
+			if filterState != list.Unfiltered {
+				return m, nil
+			}
+			if m.tree.hasChildren(string(folder)) && !m.tree.collapsed[string(folder)] {
+				m.tree.toggle(string(folder))
+				return m, m.refresh(folder)
+			}
+			if parent := path.Dir(string(folder)); parent != "." {
+				return m, m.refresh(Folder(parent))
+			}
+			return m, nil
 		}
 	}
 
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
+	// collapsed folders are searchable while filtering
+	if (filterState == list.Unfiltered) != (m.list.FilterState() == list.Unfiltered) {
+		folder, _ := m.list.SelectedItem().(Folder)
+		return m, tea.Batch(cmd, m.refresh(folder))
+	}
 	return m, cmd
 }
 
+// refresh updates the list items after the tree or the filter state is
+// changed, and selects the folder.
+func (m *folderSelectModel) refresh(folder Folder) tea.Cmd {
+	items := m.tree.items()
+	if m.list.FilterState() != list.Unfiltered {
+		items = m.tree.allItems()
+	}
+	cmd := m.list.SetItems(items)
+	if m.list.FilterState() == list.Unfiltered {
+		for i, item := range items {
+			if item.(Folder) == folder {
+				m.list.Select(i)
+				break
+			}
+		}
+	}
+	return cmd
+}
+
 func (m folderSelectModel) View() string {
 	if m.choice != "" {
 		return folderQuitTextStyle.Render(fmt.Sprintf("Switched to folder: %s", m.choice))
@@ -144,31 +324,37 @@ func getFolders(snippets []Snippet) []string {
 	for folder := range folderSet {
 		folders = append(folders, folder)
 	}
-	slices.Sort(folders)
+	slices.SortFunc(folders, compareFolders)
 
 	return folders
 }
 
 func setFolder(config *Config, snippets []Snippet) error {
-	folders := getFolders(snippets)
+	tree := newFolderTree(snippets)
+	tree.reveal(config.FolderName)
 
 	// convert to list items
-	var items []list.Item
+	items := tree.items()
 	currentIndex := 0
-	for i, folder := range folders {
-		items = append(items, Folder(folder))
-		if folder == config.FolderName {
+	for i, item := range items {
+		if string(item.(Folder)) == config.FolderName {
 			currentIndex = i
 		}
 	}
 
 	// create list
-	l := list.New(items, folderSelectDelegate{}, 30, 14)
+	l := list.New(items, folderSelectDelegate{tree}, 30, 14)
 	l.Title = "Choose a folder"
 	l.SetShowStatusBar(false)
 	l.Styles.Title = folderTitleStyle
 	l.Styles.PaginationStyle = folderPaginationStyle
 	l.Styles.HelpStyle = folderHelpStyle
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys(" ", "right", "l"), key.WithHelp("→/space", "toggle")),
+			key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←", "collapse")),
+		}
+	}
 
 	// set current selected item
 	l.Select(currentIndex)
@@ -176,6 +362,7 @@ func setFolder(config *Config, snippets []Snippet) error {
 	// run interactive program
 	p := tea.NewProgram(folderSelectModel{
 		list:    l,
+		tree:    tree,
 		config:  config,
 		quiting: false,
 	})
@@ -188,14 +375,16 @@ func setFolder(config *Config, snippets []Snippet) error {
 }
 
 func listFolders(config Config, snippets []Snippet) error {
-	folders := getFolders(snippets)
+	tree := newFolderTree(snippets)
 
-	// show folders list, current selected folder with arrow mark
-	for _, folder := range folders {
+	// show folders tree, current selected folder with arrow mark
+	for _, folder := range tree.folders {
+		f := Folder(folder)
+		name := strings.Repeat("  ", f.Depth()) + f.Name()
 		if folder == config.FolderName {
-			fmt.Printf("%s\n", folderSelectedItemStyle.Render("> "+folder))
+			fmt.Printf("%s\n", folderSelectedItemStyle.Render("> "+name))
 		} else {
-			fmt.Printf("%s\n", folderItemStyle.Render(folder))
+			fmt.Printf("%s\n", folderItemStyle.Render(name))
 		}
 	}
 	return nil
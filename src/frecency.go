@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// usageFileName is the file of the usage counts in the mdf home. The counts
+// are per user, so they are kept out of the snippets file of the repo.
+const usageFileName = "usage.json"
+
+// usage counts the opens and copies of a snippet or section, it is saved in
+// snippet-config.json for the frecency ranking.
+type usage struct {
+	Opens  int       `json:"opens"`
+	Copies int       `json:"copies"`
+	Last   time.Time `json:"last_used"`
+}
+
+// record returns a copy of the usage with one more open or copy.
+func (u *usage) record(copied bool, now time.Time) *usage {
+	var next usage
+	if u != nil {
+		next = *u
+	}
+	if copied {
+		next.Copies++
+	} else {
+		next.Opens++
+	}
+	next.Last = now
+	return &next
+}
+
+// frecency combines how often and how recently it was used, a copy counts
+// twice as much as an open.
+func (u *usage) frecency(now time.Time) float64 {
+	if u == nil {
+		return 0
+	}
+
+	weight := 0.25
+	switch age := now.Sub(u.Last); {
+	case age < time.Hour:
+		weight = 4
+	case age < Day:
+		weight = 2
+	case age < Week:
+		weight = 1
+	case age < Month:
+		weight = 0.5
+	}
+	return float64(u.Opens+2*u.Copies) * weight
+}
+
+// frecencyBonus is added to the fuzzy match score, it grows slowly so a
+// frecent item can't beat a much better match.
+func frecencyBonus(frecency float64) int {
+	return int(math.Round(8 * math.Log2(1+frecency)))
+}
+
+// bestMatch returns the index of the best fuzzy match, ranked by the match
+// score and the frecency of the item.
+func bestMatch(matches fuzzy.Matches, frecency func(index int) float64) int {
+	best, bestScore := -1, 0
+	for _, match := range matches {
+		score := match.Score + frecencyBonus(frecency(match.Index))
+		if best < 0 || score > bestScore {
+			best, bestScore = match.Index, score
+		}
+	}
+	return best
+}
+
+// withUsage returns the snippet with an open or copy of the snippet and of the
+// section, if any, recorded.
+func (s Snippet) withUsage(section string, copied bool, now time.Time) Snippet {
+	s.Usage = s.Usage.record(copied, now)
+	if section != "" {
+		sectionUsage := make(map[string]*usage, len(s.SectionUsage)+1)
+		maps.Copy(sectionUsage, s.SectionUsage)
+		sectionUsage[section] = s.SectionUsage[section].record(copied, now)
+		s.SectionUsage = sectionUsage
+	}
+	return s
+}
+
+// recordSnippetUsage records an open or copy of the snippet and section in the
+// snippets, and writes the usage file.
+func recordSnippetUsage(config Config, snippets []Snippet, snippet Snippet, section string, copied bool) {
+	for i := range snippets {
+		if snippets[i].key() == snippet.key() {
+			snippets[i] = snippets[i].withUsage(section, copied, time.Now())
+			writeUsage(config, snippets[i:i+1])
+			return
+		}
+	}
+}
+
+// moveSnippetUsage moves the usage of the snippet in the usage file to the
+// moved snippet, an empty snippet drops it.
+func moveSnippetUsage(config Config, from, to Snippet) {
+	from.Usage, from.SectionUsage = nil, nil
+	snippets := []Snippet{from}
+	if to.File != "" {
+		snippets = append(snippets, to)
+	}
+	writeUsage(config, snippets)
+}
+
+// snippetUsage is the usage of a snippet and of its sections in the usage
+// file.
+type snippetUsage struct {
+	Usage    *usage            `json:"usage,omitempty"`
+	Sections map[string]*usage `json:"sections,omitempty"`
+}
+
+// usageKey returns the <repo>/<folder>/<file> key of the snippet in the usage
+// file.
+func usageKey(config Config, snippet Snippet) string {
+	repo := snippet.Repo
+	if repo == "" {
+		repo = config.getRepoName()
+	}
+	return repo + "/" + filepath.ToSlash(snippet.Path())
+}
+
+// readUsage reads the usage file, it is empty if the file doesn't exist.
+func readUsage(config Config) (map[string]snippetUsage, error) {
+	usages := make(map[string]snippetUsage)
+	b, err := os.ReadFile(filepath.Join(config.Home, usageFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return usages, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &usages); err != nil {
+		return nil, fmt.Errorf("invalid usage file: %w", err)
+	}
+	return usages, nil
+}
+
+// applyUsage sets the usage of the snippets from the usage file.
+func applyUsage(config Config, snippets []Snippet) {
+	usages, err := readUsage(config)
+	if err != nil {
+		fmt.Println("Unable to read usage.", err)
+		return
+	}
+	for i := range snippets {
+		u := usages[usageKey(config, snippets[i])]
+		snippets[i].Usage, snippets[i].SectionUsage = u.Usage, u.Sections
+	}
+}
+
+// writeUsage writes the usage of the snippets to the usage file, the usage of
+// other snippets is kept.
+func writeUsage(config Config, snippets []Snippet) {
+	usages, err := readUsage(config)
+	if err != nil {
+		fmt.Println("Unable to read usage.", err)
+		return
+	}
+	for _, snippet := range snippets {
+		key := usageKey(config, snippet)
+		if snippet.Usage == nil && len(snippet.SectionUsage) == 0 {
+			delete(usages, key)
+			continue
+		}
+		usages[key] = snippetUsage{Usage: snippet.Usage, Sections: snippet.SectionUsage}
+	}
+
+	b, err := json.MarshalIndent(usages, "", "  ")
+	if err != nil {
+		fmt.Println("Could not marshal usage.", err)
+		return
+	}
+	if err = os.MkdirAll(config.Home, os.ModePerm); err == nil {
+		err = os.WriteFile(filepath.Join(config.Home, usageFileName), append(b, '\n'), 0o600)
+	}
+	if err != nil {
+		fmt.Println("Could not save usage file.", err)
+	}
+}
+
+// compareFrecency sorts the most frecent first.
+func compareFrecency(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
+
+// recordUsage records an open or copy of the selected snippet, and of the
+// selected section outside of the snippet pane.
+func (m *Model) recordUsage(copied bool) {
+	snippets := m.Snippets()
+	if snippets == nil || len(snippets.Items()) == 0 {
+		return
+	}
+	section := ""
+	if m.pane != snippetPane {
+		section = m.selectedSection().Slug
+	}
+	snippets.SetItem(snippets.Index(), m.selectedSnippet().withUsage(section, copied, time.Now()))
+}
+
+// sectionOpenDelay is how long a section stays selected before it counts as
+// an open, so moving through the sections doesn't record each one.
+const sectionOpenDelay = time.Second
+
+// sectionOpenedMsg is sent sectionOpenDelay after the section was selected.
+type sectionOpenedMsg struct {
+	snippet string
+	section string
+}
+
+// scheduleSectionOpen records an open of the selected section once it has
+// stayed selected for sectionOpenDelay.
+func (m *Model) scheduleSectionOpen() tea.Cmd {
+	msg := sectionOpenedMsg{m.selectedSnippet().key(), m.selectedSection().Slug}
+	return tea.Tick(sectionOpenDelay, func(time.Time) tea.Msg {
+		return msg
+	})
+}
+
+// recordSectionOpen records the open of the section if it is still selected.
+// Selecting the last recorded section of a snippet again records nothing.
+func (m *Model) recordSectionOpen(msg sectionOpenedMsg) {
+	if msg.section == "" || m.selectedSnippet().key() != msg.snippet || m.selectedSection().Slug != msg.section {
+		return
+	}
+	if m.openedSections[msg.snippet] == msg.section {
+		return
+	}
+	if m.openedSections == nil {
+		m.openedSections = make(map[string]string)
+	}
+	m.openedSections[msg.snippet] = msg.section
+	snippets := m.Snippets()
+	snippets.SetItem(snippets.Index(), m.selectedSnippet().withUsage(msg.section, false, time.Now()))
+}
+
+// toggleFrecentSort sorts the snippet and section lists by frecency, or back
+// to their default order.
+func (m *Model) toggleFrecentSort() tea.Cmd {
+	m.sortFrecent = !m.sortFrecent
+	if m.sortFrecent {
+		m.snippetOrder = make(map[string]int)
+		for _, snippetList := range m.SnippetsMap {
+			for i, item := range snippetList.Items() {
+				m.snippetOrder[item.(Snippet).key()] = i
+			}
+		}
+	}
+	for _, snippetList := range m.SnippetsMap {
+		m.sortSnippets(snippetList)
+	}
+	// the sections are sorted when they are read again
+	m.SectionsMap = make(map[string]*list.Model)
+	return m.updateContent()
+}
+
+// sortSnippets sorts the snippet list by frecency, or by the order before the
+// frecent sort. The selected snippet stays selected.
+func (m *Model) sortSnippets(snippetList *list.Model) {
+	items := snippetList.Items()
+	if len(items) == 0 {
+		return
+	}
+	selected := items[snippetList.Index()].(Snippet).key()
+
+	now := time.Now()
+	slices.SortStableFunc(items, func(a, b list.Item) int {
+		if m.sortFrecent {
+			return compareFrecency(a.(Snippet).Usage.frecency(now), b.(Snippet).Usage.frecency(now))
+		}
+		// snippets created while sorted come first
+		return m.snippetOrder[a.(Snippet).key()] - m.snippetOrder[b.(Snippet).key()]
+	})
+	snippetList.SetItems(items)
+	for i, item := range items {
+		if item.(Snippet).key() == selected {
+			snippetList.Select(i)
+			break
+		}
+	}
+}
+
+// sortSections sorts the sections of the snippet by frecency when the frecent
+// sort is on.
+func (m *Model) sortSections(snippet Snippet, sections []Section) {
+	if !m.sortFrecent {
+		return
+	}
+	now := time.Now()
+	slices.SortStableFunc(sections, func(a, b Section) int {
+		return compareFrecency(snippet.SectionUsage[a.Slug].frecency(now), snippet.SectionUsage[b.Slug].frecency(now))
+	})
+}
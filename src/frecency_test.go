@@ -0,0 +1,186 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sahilm/fuzzy"
+	"golang.org/x/exp/slices"
+)
+
+func TestUsageFrecency(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		usage *usage
+		want  float64
+	}{
+		{"never used", nil, 0},
+		{"within an hour", &usage{Opens: 1, Copies: 1, Last: now.Add(-30 * time.Minute)}, 12},
+		{"within a day", &usage{Opens: 1, Copies: 1, Last: now.Add(-5 * time.Hour)}, 6},
+		{"within a week", &usage{Opens: 1, Copies: 1, Last: now.Add(-3 * Day)}, 3},
+		{"within a month", &usage{Opens: 1, Copies: 1, Last: now.Add(-2 * Week)}, 1.5},
+		{"older", &usage{Opens: 1, Copies: 1, Last: now.Add(-2 * Month)}, 0.75},
+		{"copies count twice", &usage{Copies: 2, Last: now}, 16},
+	}
+	for _, tt := range tests {
+		if got := tt.usage.frecency(now); got != tt.want {
+			t.Errorf("%s: frecency = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUsageRecord(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var u *usage
+	opened := u.record(false, now)
+	copied := opened.record(true, now.Add(time.Minute))
+	if *opened != (usage{Opens: 1, Last: now}) {
+		t.Errorf("record open = %+v", *opened)
+	}
+	if *copied != (usage{Opens: 1, Copies: 1, Last: now.Add(time.Minute)}) {
+		t.Errorf("record copy = %+v", *copied)
+	}
+}
+
+func TestWithUsageBySlug(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// two sections titled "Foo" have the slugs foo and foo-1
+	snippet := Snippet{Folder: "k8s", File: "pods.md"}.
+		withUsage("foo", true, now).
+		withUsage("foo-1", false, now).
+		withUsage("foo-1", false, now)
+	if got := snippet.SectionUsage["foo"]; got == nil || got.Copies != 1 || got.Opens != 0 {
+		t.Errorf("usage of foo = %+v, want 1 copy", got)
+	}
+	if got := snippet.SectionUsage["foo-1"]; got == nil || got.Opens != 2 || got.Copies != 0 {
+		t.Errorf("usage of foo-1 = %+v, want 2 opens", got)
+	}
+	if snippet.Usage.Opens != 2 || snippet.Usage.Copies != 1 {
+		t.Errorf("snippet usage = %+v, want 2 opens and 1 copy", snippet.Usage)
+	}
+}
+
+func TestCompareFrecency(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sections := []Section{{Slug: "unused"}, {Slug: "old"}, {Slug: "recent"}, {Slug: "copied"}, {Slug: "unused-1"}}
+	snippet := Snippet{SectionUsage: map[string]*usage{
+		"old":    {Opens: 4, Last: now.Add(-2 * Month)},
+		"recent": {Opens: 1, Last: now.Add(-time.Minute)},
+		"copied": {Copies: 1, Last: now.Add(-time.Minute)},
+	}}
+	slices.SortStableFunc(sections, func(a, b Section) int {
+		return compareFrecency(snippet.SectionUsage[a.Slug].frecency(now), snippet.SectionUsage[b.Slug].frecency(now))
+	})
+	var got []string
+	for _, section := range sections {
+		got = append(got, section.Slug)
+	}
+	// ties keep their order
+	want := []string{"copied", "recent", "old", "unused", "unused-1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted by frecency = %q, want %q", got, want)
+	}
+}
+
+func TestFrecencyBonus(t *testing.T) {
+	tests := []struct {
+		frecency float64
+		want     int
+	}{
+		{0, 0},
+		{1, 8},
+		{3, 16},
+		{15, 32},
+	}
+	for _, tt := range tests {
+		if got := frecencyBonus(tt.frecency); got != tt.want {
+			t.Errorf("frecencyBonus(%v) = %d, want %d", tt.frecency, got, tt.want)
+		}
+	}
+}
+
+func TestBestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		matches  fuzzy.Matches
+		frecency []float64
+		want     int
+	}{
+		{
+			name:     "best score without usage",
+			matches:  fuzzy.Matches{{Index: 0, Score: 10}, {Index: 1, Score: 30}},
+			frecency: []float64{0, 0},
+			want:     1,
+		},
+		{
+			name:     "frecency breaks a tie",
+			matches:  fuzzy.Matches{{Index: 0, Score: 20}, {Index: 1, Score: 20}},
+			frecency: []float64{0, 1},
+			want:     1,
+		},
+		{
+			name:     "first match wins an exact tie",
+			matches:  fuzzy.Matches{{Index: 1, Score: 20}, {Index: 0, Score: 20}},
+			frecency: []float64{2, 2},
+			want:     1,
+		},
+		{
+			name:     "frecency beats a slightly better match",
+			matches:  fuzzy.Matches{{Index: 0, Score: 25}, {Index: 1, Score: 20}},
+			frecency: []float64{0, 3},
+			want:     1,
+		},
+		{
+			name:     "frecency doesn't beat a much better match",
+			matches:  fuzzy.Matches{{Index: 0, Score: 60}, {Index: 1, Score: 20}},
+			frecency: []float64{0, 15},
+			want:     0,
+		},
+	}
+	for _, tt := range tests {
+		got := bestMatch(tt.matches, func(i int) float64 {
+			return tt.frecency[i]
+		})
+		if got != tt.want {
+			t.Errorf("%s: bestMatch = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUsageFile(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	config := Config{Home: t.TempDir(), RepoName: "kugarocks/rockman"}
+	used := Snippet{Folder: "k8s", File: "pods.md"}.withUsage("logs", true, now)
+	other := Snippet{Folder: "team", File: "deploy.md", Repo: "team/notes"}.withUsage("", false, now)
+	writeUsage(config, []Snippet{used, other})
+
+	snippets := []Snippet{
+		{Folder: "k8s", File: "pods.md"},
+		{Folder: "k8s", File: "logs.md"},
+		{Folder: "team", File: "deploy.md", Repo: "team/notes"},
+	}
+	applyUsage(config, snippets)
+	if got := snippets[0].SectionUsage["logs"]; got == nil || got.Copies != 1 {
+		t.Errorf("usage of pods.md#logs = %+v, want 1 copy", got)
+	}
+	if snippets[1].Usage != nil {
+		t.Errorf("usage of logs.md = %+v, want none", snippets[1].Usage)
+	}
+	if got := snippets[2].Usage; got == nil || got.Opens != 1 {
+		t.Errorf("usage of team/notes deploy.md = %+v, want 1 open", got)
+	}
+
+	// a snippet without usage is dropped, the others are kept
+	writeUsage(config, []Snippet{{Folder: "k8s", File: "pods.md"}})
+	usages, err := readUsage(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := usages["kugarocks/rockman/k8s/pods.md"]; ok {
+		t.Errorf("usage of pods.md is kept after it was cleared")
+	}
+	if _, ok := usages["team/notes/team/deploy.md"]; !ok {
+		t.Errorf("usage of team/notes deploy.md is dropped, want it kept")
+	}
+}
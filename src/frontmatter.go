@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// markdownExtensions are the extensions of the snippet files with front
+// matter and sections headings, other files are listed as they are.
+var markdownExtensions = []string{"md", "markdown"}
+
+// FrontMatter is the YAML metadata at the top of a snippet file.
+type FrontMatter struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Tags        yamlList `yaml:"tags"`
+	Aliases     yamlList `yaml:"aliases"`
+	Icon        string   `yaml:"icon"`
+	Order       int      `yaml:"order"`
+}
+
+// yamlList is a YAML list that may also be written as a comma separated
+// string, like tags: k8s, debug.
+type yamlList []string
+
+func (l *yamlList) UnmarshalYAML(value *yaml.Node) error {
+	var items []string
+	if value.Kind == yaml.ScalarNode {
+		items = strings.Split(value.Value, ",")
+	} else if err := value.Decode(&items); err != nil {
+		return err
+	}
+
+	*l = nil
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// splitFrontMatter splits the content into the front matter between the
+// leading "---" lines and the rest of the content. The front matter is empty
+// if the content doesn't start with one, or if it isn't a YAML mapping, like
+// a file starting with a "---" section break.
+func splitFrontMatter(content string) (frontMatter, body string) {
+	first, rest, ok := strings.Cut(content, "\n")
+	if !ok || strings.TrimRight(first, " \t\r") != frontMatterDelimiter {
+		return "", content
+	}
+
+	var lines []string
+	for rest != "" {
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+		line = strings.TrimRight(line, "\r")
+		if end := strings.TrimRight(line, " \t"); end == frontMatterDelimiter || end == "..." {
+			frontMatter = strings.Join(lines, "\n")
+			var fields map[string]any
+			if err := yaml.Unmarshal([]byte(frontMatter), &fields); err != nil || len(fields) == 0 {
+				return "", content
+			}
+			return frontMatter, rest
+		}
+		lines = append(lines, line)
+	}
+	// without the closing line it's not front matter
+	return "", content
+}
+
+// parseFrontMatter parses the front matter of the content. Invalid front
+// matter is ignored, so the snippet is still listed.
+func parseFrontMatter(content string) FrontMatter {
+	var fm FrontMatter
+	frontMatter, _ := splitFrontMatter(content)
+	if frontMatter == "" {
+		return fm
+	}
+	if err := yaml.Unmarshal([]byte(frontMatter), &fm); err != nil {
+		return FrontMatter{}
+	}
+	return fm
+}
+
+// withFrontMatter returns the snippet with the metadata of the front matter,
+// the title overrides the name of the file.
+func (s Snippet) withFrontMatter(fm FrontMatter) Snippet {
+	s.Name = strings.TrimSuffix(s.File, filepath.Ext(s.File))
+	if title := strings.TrimSpace(fm.Title); title != "" {
+		s.Name = title
+	}
+	s.Description = strings.TrimSpace(fm.Description)
+	s.Tags = appendTags(nil, fm.Tags...)
+	s.Aliases = fm.Aliases
+	s.Icon = strings.TrimSpace(fm.Icon)
+	s.Order = fm.Order
+	return s
+}
+
+// readFrontMatter returns the snippet with the metadata of the front matter of
+// its file. The tags of the section headings are tags of the snippet too. Only
+// markdown files are read.
+func readFrontMatter(config Config, snippet Snippet) Snippet {
+	if !includeExtension(markdownExtensions, snippet.File) {
+		return snippet
+	}
+	content, err := os.ReadFile(config.getSnippetPath(snippet))
+	if err != nil {
+		return snippet
+	}
+	snippet = snippet.withFrontMatter(parseFrontMatter(string(content)))
+	for _, section := range parseSections(snippet, string(content), config.SectionSplit) {
+		snippet.Tags = appendTags(snippet.Tags, section.Tags...)
+	}
+	return snippet
+}
+
+// compareSnippetOrder sorts the snippets by the order of the front matter,
+// snippets without an order keep their position after the others.
+func compareSnippetOrder(a, b Snippet) int {
+	switch {
+	case a.Order == b.Order:
+		return 0
+	case a.Order == 0:
+		return 1
+	case b.Order == 0:
+		return -1
+	}
+	return a.Order - b.Order
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		frontMatter string
+		body        string
+	}{
+		{
+			name:        "mapping",
+			content:     "---\ntitle: Pods\ntags: [k8s]\n---\n# Pods\n",
+			frontMatter: "title: Pods\ntags: [k8s]",
+			body:        "# Pods\n",
+		},
+		{
+			name:        "closed by dots",
+			content:     "---\ntitle: Pods\n...\n# Pods\n",
+			frontMatter: "title: Pods",
+			body:        "# Pods\n",
+		},
+		{
+			name:        "crlf",
+			content:     "---\r\ntitle: Pods\r\n---\r\n# Pods\r\n",
+			frontMatter: "title: Pods",
+			body:        "# Pods\r\n",
+		},
+		{
+			name:    "no front matter",
+			content: "# Pods\n\n---\n\n# Logs\n",
+			body:    "# Pods\n\n---\n\n# Logs\n",
+		},
+		{
+			name:    "not a mapping",
+			content: "---\n- pods\n- logs\n---\n# Pods\n",
+			body:    "---\n- pods\n- logs\n---\n# Pods\n",
+		},
+		{
+			name:    "invalid yaml",
+			content: "---\ntitle: [pods\n---\n# Pods\n",
+			body:    "---\ntitle: [pods\n---\n# Pods\n",
+		},
+		{
+			name:    "unterminated",
+			content: "---\ntitle: Pods\n# Pods\n",
+			body:    "---\ntitle: Pods\n# Pods\n",
+		},
+		{
+			name:    "hr between sections",
+			content: "---\n# Pods\n\nkubectl get pods\n\n---\n# Logs\n",
+			body:    "---\n# Pods\n\nkubectl get pods\n\n---\n# Logs\n",
+		},
+		{
+			name:    "empty",
+			content: "---\n---\n# Pods\n",
+			body:    "---\n---\n# Pods\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontMatter, body := splitFrontMatter(tt.content)
+			if frontMatter != tt.frontMatter || body != tt.body {
+				t.Errorf("splitFrontMatter(%q) = %q, %q, want %q, %q", tt.content, frontMatter, body, tt.frontMatter, tt.body)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	fm := parseFrontMatter("---\ntitle: Pods\ntags: k8s, debug\naliases:\n  - po\norder: 2\n---\n# Pods\n")
+	if fm.Title != "Pods" || fm.Order != 2 {
+		t.Errorf("parseFrontMatter title and order = %q, %d", fm.Title, fm.Order)
+	}
+	if !slices.Equal(fm.Tags, []string{"k8s", "debug"}) || !slices.Equal(fm.Aliases, []string{"po"}) {
+		t.Errorf("parseFrontMatter tags and aliases = %q, %q", fm.Tags, fm.Aliases)
+	}
+}
+
+func TestScanSnippetsFrontMatter(t *testing.T) {
+	config := newConfig()
+	config.Home = t.TempDir()
+	config.RepoName = "local/repo"
+	dir := filepath.Join(config.getRepoPath(), "k8s")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, content string, modTime time.Time) {
+		t.Helper()
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(snippets []Snippet) []string {
+		var names []string
+		for _, snippet := range snippets {
+			names = append(names, snippet.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	old := time.Now().Add(-time.Hour)
+	writeFile("pods.md", "---\ntitle: Pods\n---\n# Pods\n", old)
+	writeFile("logo.png", "---\ntitle: Logo\n---\n", old)
+	snippets := scanSnippets(config, nil)
+	if got, want := names(snippets), []string{"Pods", "logo"}; !slices.Equal(got, want) {
+		t.Fatalf("new snippets = %q, want %q", got, want)
+	}
+
+	// an older file isn't read again
+	writeFile("pods.md", "---\ntitle: Old Pods\n---\n# Pods\n", old)
+	snippets = scanSnippets(config, snippets)
+	if got, want := names(snippets), []string{"Pods", "logo"}; !slices.Equal(got, want) {
+		t.Errorf("unmodified snippets = %q, want %q", got, want)
+	}
+
+	writeFile("pods.md", "---\ntitle: New Pods\n---\n# Pods\n", time.Now().Add(time.Hour))
+	snippets = scanSnippets(config, snippets)
+	if got, want := names(snippets), []string{"New Pods", "logo"}; !slices.Equal(got, want) {
+		t.Errorf("modified snippets = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// runGit runs git in the directory and returns the trimmed stdout. The error
+// includes the stderr of git.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	// never wait for credentials, the commands may run in parallel
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			msg, _, _ := strings.Cut(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isGitRepo reports whether the directory is the root of a git work tree.
+func isGitRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// gitHead returns the commit of HEAD, or an empty string for a repo without
+// commits.
+func gitHead(dir string) string {
+	head, _ := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD")
+	return head
+}
+
+// gitPull fast-forwards the repo and returns the commits before and after.
+func gitPull(dir string) (before, after string, err error) {
+	before = gitHead(dir)
+	if before == "" {
+		// pulling from an empty remote fails, there is nothing to update
+		if heads, err := runGit(dir, "ls-remote", "--heads", "origin"); err == nil && heads == "" {
+			return "", "", nil
+		}
+	}
+	if _, err = runGit(dir, "pull", "--ff-only", "--quiet"); err != nil {
+		return before, before, err
+	}
+	return before, gitHead(dir), nil
+}
+
+// gitCommitCount returns the number of commits between from and to, or up to
+// to if from is empty.
+func gitCommitCount(dir, from, to string) int {
+	revs := to
+	if from != "" {
+		revs = from + ".." + to
+	}
+	out, err := runGit(dir, "rev-list", "--count", revs)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(out)
+	return n
+}
+
+// gitStatus holds the branch, the commits ahead and behind the upstream and
+// the number of changed files of a repo.
+type gitStatus struct {
+	Branch   string
+	Upstream string
+	Ahead    int
+	Behind   int
+	Changed  int
+}
+
+// readGitStatus returns the status of the repo against the last fetched
+// upstream.
+func readGitStatus(dir string) (gitStatus, error) {
+	var status gitStatus
+	branch, err := runGit(dir, "symbolic-ref", "--short", "--quiet", "HEAD")
+	if err != nil {
+		// a detached HEAD has no branch
+		if branch, err = runGit(dir, "rev-parse", "--short", "HEAD"); err != nil {
+			return status, err
+		}
+	}
+	status.Branch = branch
+
+	changes, err := runGit(dir, "status", "--porcelain")
+	if err != nil {
+		return status, err
+	}
+	if changes != "" {
+		status.Changed = len(strings.Split(changes, "\n"))
+	}
+
+	// a branch without upstream is neither ahead nor behind
+	upstream, err := runGit(dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return status, nil
+	}
+	status.Upstream = upstream
+
+	counts, err := runGit(dir, "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		return status, err
+	}
+	if fields := strings.Fields(counts); len(fields) == 2 {
+		status.Ahead, _ = strconv.Atoi(fields[0])
+		status.Behind, _ = strconv.Atoi(fields[1])
+	}
+	return status, nil
+}
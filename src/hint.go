@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	bkey "github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/exp/slices"
+)
+
+const defaultHintAlphabet = "asdfghjkl"
+
+// hintMode labels every code block of the section, typing a label copies
+// the code block.
+type hintMode struct {
+	active bool
+	labels []string
+	typed  string
+}
+
+// hintLabels returns n labels of the same length made of the lowercase
+// alphabet, so no label is the prefix of another.
+func hintLabels(alphabet string, n int) []string {
+	var chars []string
+	for _, r := range strings.ToLower(alphabet) {
+		if c := string(r); !slices.Contains(chars, c) {
+			chars = append(chars, c)
+		}
+	}
+	if len(chars) < 2 {
+		return hintLabels(defaultHintAlphabet, n)
+	}
+
+	length := 1
+	for count := len(chars); count < n; count *= len(chars) {
+		length++
+	}
+
+	labels := make([]string, n)
+	for i := range labels {
+		label := make([]string, length)
+		for j, rest := length-1, i; j >= 0; j-- {
+			label[j] = chars[rest%len(chars)]
+			rest /= len(chars)
+		}
+		labels[i] = strings.Join(label, "")
+	}
+	return labels
+}
+
+// label returns the label of the i-th code block while it matches the typed
+// characters.
+func (h hintMode) label(i int) (string, bool) {
+	if !h.active || i >= len(h.labels) || !strings.HasPrefix(h.labels[i], h.typed) {
+		return "", false
+	}
+	return h.labels[i], true
+}
+
+// startHints labels the code blocks of the selected section.
+func (m *Model) startHints() tea.Cmd {
+	if m.run != nil && m.run.visible {
+		return nil
+	}
+	section := m.selectedSection()
+	if len(section.CodeBlocks) == 0 {
+		return m.showError(fmt.Errorf("section %q has no code blocks", section.Title))
+	}
+	m.hints = hintMode{active: true, labels: hintLabels(m.config.HintAlphabet, len(section.CodeBlocks))}
+	return m.updateContent()
+}
+
+// stopHints removes the labels.
+func (m *Model) stopHints() tea.Cmd {
+	m.hints = hintMode{}
+	return m.updateContent()
+}
+
+// updateHints handles the keys of the hint mode, the code block is copied
+// once its label is typed.
+func (m *Model) updateHints(msg tea.KeyMsg) tea.Cmd {
+	if bkey.Matches(msg, m.keys.Hint) {
+		return m.stopHints()
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return m.stopHints()
+	case tea.KeyBackspace:
+		if m.hints.typed != "" {
+			m.hints.typed = m.hints.typed[:len(m.hints.typed)-1]
+			return m.updateContent()
+		}
+		return m.stopHints()
+	case tea.KeyRunes:
+	default:
+		return nil
+	}
+
+	typed := m.hints.typed + strings.ToLower(msg.String())
+	for i, label := range m.hints.labels {
+		if label == typed {
+			m.hints = hintMode{}
+			codeBlocks := m.selectedSection().CodeBlocks
+			if i >= len(codeBlocks) {
+				return m.updateContent()
+			}
+			return tea.Batch(m.updateContent(), m.copyCodeBlock(codeBlocks[i], m.config.ExitAfterCopy))
+		}
+		if strings.HasPrefix(label, typed) {
+			m.hints.typed = typed
+			return m.updateContent()
+		}
+	}
+	m.hints = hintMode{}
+	return tea.Batch(m.updateContent(), m.showError(errors.New("no code block is labeled "+typed)))
+}
+
+// hintBorder returns the border of a code block with the hint label as its
+// title.
+func (m *Model) hintBorder(label string) string {
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.config.SelectedItemFgColor))
+	border := m.paddingBorderWithTitle(label)
+	return strings.Replace(border, " "+label+" ", " "+style.Render(label)+" ", 1)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestHintLabels(t *testing.T) {
+	tests := []struct {
+		alphabet string
+		n        int
+		want     []string
+	}{
+		{"asdf", 3, []string{"a", "s", "d"}},
+		{"ASDF", 4, []string{"a", "s", "d", "f"}},
+		{"asd", 5, []string{"aa", "as", "ad", "sa", "ss"}},
+		{"aAsS", 3, []string{"aa", "as", "sa"}},
+		{"asdf", 0, []string{}},
+	}
+	for _, tt := range tests {
+		if got := hintLabels(tt.alphabet, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("hintLabels(%q, %d) = %q, want %q", tt.alphabet, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHintLabelsNoPrefix(t *testing.T) {
+	for _, alphabet := range []string{"", "a", "asdfghjkl", "ab"} {
+		labels := hintLabels(alphabet, 30)
+		if len(labels) != 30 {
+			t.Fatalf("hintLabels(%q, 30) returned %d labels", alphabet, len(labels))
+		}
+		for i, a := range labels {
+			for j, b := range labels {
+				if i != j && strings.HasPrefix(b, a) {
+					t.Errorf("hintLabels(%q, 30): %q is a prefix of %q", alphabet, a, b)
+				}
+			}
+		}
+	}
+}
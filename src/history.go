@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquilax/truncate"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	historyFileName = "history.jsonl"
+
+	historyUsage = "Usage: mdf history [<n>|clear] [--print] [--json|--format tsv]"
+
+	// the history holds the copied content, which may be a token or a
+	// password, so only the user can read it.
+	historyFileMode = 0o600
+
+	historyOverlayHeight = 10
+	historyPreviewLines  = 8
+)
+
+var (
+	historyItemStyle  = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("252"))
+	historyFocusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	historyTimeStyle  = lipgloss.NewStyle().Width(12).Foreground(lipgloss.Color("241"))
+)
+
+// historyEntry is a copy saved in the history file. Section is empty for the
+// whole snippet file, Block is the 1-based code block index or 0.
+type historyEntry struct {
+	Time    time.Time `json:"time"`
+	Repo    string    `json:"repo"`
+	Folder  string    `json:"folder"`
+	File    string    `json:"file"`
+	Section string    `json:"section,omitempty"`
+	Block   int       `json:"block,omitempty"`
+	Content string    `json:"content"`
+}
+
+func newHistoryEntry(config Config, target copyTarget) historyEntry {
+	repo := target.Snippet.Repo
+	if repo == "" {
+		repo = config.getRepoName()
+	}
+	return historyEntry{
+		Time:    time.Now(),
+		Repo:    repo,
+		Folder:  target.Snippet.Folder,
+		File:    target.Snippet.File,
+		Section: target.Section,
+		Block:   target.Block,
+		Content: target.Content,
+	}
+}
+
+// source returns <repo>/<folder>/<file>#<section> with the block index.
+func (e historyEntry) source() string {
+	source := filepath.Join(e.Repo, e.Folder, e.File)
+	if e.Section != "" {
+		source += "#" + e.Section
+	}
+	if e.Block > 0 {
+		source += fmt.Sprintf(" [%d]", e.Block)
+	}
+	return source
+}
+
+// preview returns the first line of the content that isn't blank.
+func (e historyEntry) preview(width int) string {
+	for _, line := range strings.Split(e.Content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return truncate.Truncate(line, width, "...", truncate.PositionEnd)
+		}
+	}
+	return ""
+}
+
+// readHistory reads the history file, the oldest entry first. Lines that
+// can't be parsed are skipped. The entries beyond the history limits are
+// pruned from the file.
+func readHistory(config Config) ([]historyEntry, error) {
+	entries, err := readHistoryFile(config)
+	if err != nil {
+		return nil, err
+	}
+	kept := pruneHistory(entries, config.HistoryMaxEntries, config.HistoryMaxDays, time.Now())
+	if len(kept) < len(entries) {
+		if err = writeHistory(config, kept); err != nil {
+			return nil, err
+		}
+	}
+	return kept, nil
+}
+
+// readHistoryFile reads every entry of the history file.
+func readHistoryFile(config Config) ([]historyEntry, error) {
+	f, err := os.Open(filepath.Join(config.Home, historyFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var entry historyEntry
+			if json.Unmarshal(line, &entry) == nil {
+				entries = append(entries, entry)
+			}
+		}
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read history: %w", err)
+		}
+	}
+}
+
+// writeHistory replaces the history file with the entries.
+func writeHistory(config Config, entries []historyEntry) error {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return fmt.Errorf("unable to serialize history: %w", err)
+		}
+	}
+
+	file := filepath.Join(config.Home, historyFileName)
+	if err := os.WriteFile(file, b.Bytes(), historyFileMode); err != nil {
+		return fmt.Errorf("unable to write history file %s: %w", file, err)
+	}
+	return nil
+}
+
+// appendHistory appends the entry to the history file, the old entries are
+// pruned when the history is read.
+func appendHistory(config Config, entry historyEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to serialize history: %w", err)
+	}
+	file := filepath.Join(config.Home, historyFileName)
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, historyFileMode)
+	if err != nil {
+		return fmt.Errorf("unable to write history file %s: %w", file, err)
+	}
+	defer f.Close()
+	if _, err = f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("unable to write history file %s: %w", file, err)
+	}
+	return nil
+}
+
+// pruneHistory drops the entries older than maxDays, then the oldest entries
+// beyond maxEntries. A limit of 0 or less keeps everything.
+func pruneHistory(entries []historyEntry, maxEntries, maxDays int, now time.Time) []historyEntry {
+	kept := entries
+	if maxDays > 0 {
+		cutoff := now.Add(-time.Duration(maxDays) * Day)
+		kept = make([]historyEntry, 0, len(entries))
+		for _, entry := range entries {
+			if !entry.Time.Before(cutoff) {
+				kept = append(kept, entry)
+			}
+		}
+	}
+	if maxEntries > 0 && len(kept) > maxEntries {
+		kept = kept[len(kept)-maxEntries:]
+	}
+	return kept
+}
+
+// runHistory lists the copy history, the most recent copy first, or copies
+// an entry again.
+//
+//	mdf history         - list the copies
+//	mdf history 2       - copy the second most recent copy again
+//	mdf history clear   - remove the history file
+func runHistory(config Config, args []string) error {
+	parsed := parseArgs(args, "format")
+	entries, err := readHistory(config)
+	if err != nil {
+		return err
+	}
+
+	switch arg := parsed.arg(0); arg {
+	case "":
+		format, err := parseListFormat(parsed)
+		if err != nil {
+			return err
+		}
+		return listHistory(entries, format)
+	case "clear":
+		err = os.Remove(filepath.Join(config.Home, historyFileName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unable to clear history: %w", err)
+		}
+		return nil
+	default:
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return errors.New(historyUsage)
+		}
+		if n < 1 || n > len(entries) {
+			return fmt.Errorf("the history has %d entries, invalid entry %d", len(entries), n)
+		}
+
+		entry := entries[len(entries)-n]
+		if parsed.has("print") {
+			fmt.Println(entry.Content)
+		} else if err = copyToClipboard(config, entry.Content); err != nil {
+			return fmt.Errorf("failed to write clipboard: %w", err)
+		}
+		entry.Time = time.Now()
+		return appendHistory(config, entry)
+	}
+}
+
+// historyRecord is a history entry with its number in the list, 1 is the
+// most recent copy.
+type historyRecord struct {
+	Index int `json:"index"`
+	historyEntry
+}
+
+func listHistory(entries []historyEntry, format listFormat) error {
+	records := make([]historyRecord, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		records = append(records, historyRecord{Index: len(records) + 1, historyEntry: entries[i]})
+	}
+
+	if format == listFormatText {
+		for _, r := range records {
+			fmt.Printf("%3d  %-12s %s  %s\n", r.Index, humanizeTime(r.Time), r.source(), r.preview(60))
+		}
+		return nil
+	}
+	return writeRecords(os.Stdout, format, records,
+		[]string{"index", "time", "repo", "folder", "file", "section", "block", "content"},
+		func(r historyRecord) []string {
+			return []string{strconv.Itoa(r.Index), r.Time.Format(time.RFC3339), r.Repo, r.Folder, r.File,
+				r.Section, strconv.Itoa(r.Block), r.Content}
+		})
+}
+
+// historySelectedMsg is sent when a copy is picked to copy it again.
+type historySelectedMsg historyEntry
+
+// historyOverlay lists the recent copies, the most recent first.
+type historyOverlay struct {
+	entries []historyEntry
+	cursor  int
+}
+
+func newHistoryOverlay(entries []historyEntry) *historyOverlay {
+	recent := make([]historyEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		recent = append(recent, entries[i])
+	}
+	return &historyOverlay{entries: recent}
+}
+
+func (h *historyOverlay) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q", "ctrl+c":
+			return h, closeOverlay
+		case "up", "k", "ctrl+p":
+			if h.cursor > 0 {
+				h.cursor--
+			}
+		case "down", "j", "ctrl+n":
+			if h.cursor < len(h.entries)-1 {
+				h.cursor++
+			}
+		case "enter":
+			if len(h.entries) == 0 {
+				return h, nil
+			}
+			entry := h.entries[h.cursor]
+			return h, func() tea.Msg { return historySelectedMsg(entry) }
+		}
+	}
+	return h, nil
+}
+
+func (h *historyOverlay) View() string {
+	var b strings.Builder
+	b.WriteString(overlayTitleStyle.Render("Copy history") + "\n\n")
+
+	if len(h.entries) == 0 {
+		b.WriteString(overlayHelpStyle.Render("Nothing copied yet") + "\n\n")
+		b.WriteString(overlayHelpStyle.Render("esc close"))
+		return overlayStyle.Render(b.String())
+	}
+
+	start := max(0, h.cursor-historyOverlayHeight+1)
+	for i := start; i < len(h.entries) && i < start+historyOverlayHeight; i++ {
+		entry := h.entries[i]
+		line := historyTimeStyle.Render(humanizeTime(entry.Time)) + entry.source()
+		if i == h.cursor {
+			b.WriteString(historyFocusStyle.Render("> ") + line + "\n")
+			continue
+		}
+		b.WriteString(historyItemStyle.Render(line) + "\n")
+	}
+
+	lines := strings.Split(h.entries[h.cursor].Content, "\n")
+	if len(lines) > historyPreviewLines {
+		lines = append(lines[:historyPreviewLines], "...")
+	}
+	b.WriteString("\n" + placeholderPreviewStyle.Render(strings.Join(lines, "\n")) + "\n\n")
+	b.WriteString(overlayHelpStyle.Render("↑/↓ move • enter copy • esc close"))
+	return overlayStyle.Render(b.String())
+}
+
+// showHistory opens the copy history.
+func (m *Model) showHistory() tea.Cmd {
+	entries, err := readHistory(m.config)
+	if err != nil {
+		return m.showError(err)
+	}
+	m.overlay = newHistoryOverlay(entries)
+	return nil
+}
+
+// recordHistory appends the copy of the selected snippet, or of the block of
+// the selected section outside of the snippet pane, to the history.
+func (m *Model) recordHistory(content string, block int) {
+	target := copyTarget{Snippet: m.selectedSnippet(), Block: block, Content: content}
+	if m.pane != snippetPane {
+		target.Section = m.selectedSection().Title
+	}
+	_ = appendHistory(m.config, newHistoryEntry(m.config, target))
+}
+
+// recopy copies the content of a history entry again, it is added to the
+// history as the most recent copy.
+func (m *Model) recopy(entry historyEntry) tea.Cmd {
+	if err := m.setClipboard(entry.Content); err != nil {
+		return m.showError(err)
+	}
+	entry.Time = time.Now()
+	_ = appendHistory(m.config, entry)
+	return m.copied(m.config.ExitAfterCopy)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestPruneHistory(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []historyEntry{
+		{Time: now.Add(-100 * Day), Content: "a"},
+		{Time: now.Add(-40 * Day), Content: "b"},
+		{Time: now.Add(-2 * Day), Content: "c"},
+		{Time: now.Add(-time.Hour), Content: "d"},
+	}
+	tests := []struct {
+		name       string
+		maxEntries int
+		maxDays    int
+		want       []string
+	}{
+		{"no limits", 0, 0, []string{"a", "b", "c", "d"}},
+		{"max days", 0, 30, []string{"c", "d"}},
+		{"max days on the cutoff", 0, 40, []string{"b", "c", "d"}},
+		{"max entries", 3, 0, []string{"b", "c", "d"}},
+		{"max entries above the count", 10, 0, []string{"a", "b", "c", "d"}},
+		{"both limits", 1, 90, []string{"d"}},
+		{"negative limits", -1, -1, []string{"a", "b", "c", "d"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, entry := range pruneHistory(entries, tt.maxEntries, tt.maxDays, now) {
+			got = append(got, entry.Content)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: pruneHistory = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	config := Config{Home: t.TempDir(), HistoryMaxEntries: 2}
+	for _, content := range []string{"a", "b", "c"} {
+		if err := appendHistory(config, historyEntry{Time: time.Now(), Content: content}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file := filepath.Join(config.Home, historyFileName)
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != historyFileMode {
+		t.Errorf("history file mode = %v, want %v", mode, os.FileMode(historyFileMode))
+	}
+
+	// the entries are pruned on read, not on append
+	if entries, err := readHistoryFile(config); err != nil || len(entries) != 3 {
+		t.Fatalf("history file has %d entries, want 3 (%v)", len(entries), err)
+	}
+	entries, err := readHistory(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Content != "b" || entries[1].Content != "c" {
+		t.Errorf("readHistory = %+v, want b and c", entries)
+	}
+	if entries, err = readHistoryFile(config); err != nil || len(entries) != 2 {
+		t.Errorf("pruned history file has %d entries, want 2 (%v)", len(entries), err)
+	}
+}
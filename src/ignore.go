@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const ignoreFileName = ".mdfignore"
+
+// ignoreRule is a pattern of a .mdfignore file, with gitignore semantics.
+type ignoreRule struct {
+	// base is the slash separated directory of the .mdfignore file, relative
+	// to the repo root. It is empty for the repo root.
+	base     string
+	segments []string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreRules are the rules that apply to a directory, from the repo root
+// down. A later rule overrides an earlier one.
+type ignoreRules []ignoreRule
+
+// readIgnoreFile reads the .mdfignore file of the directory, base is the
+// directory relative to the repo root.
+func readIgnoreFile(dir, base string) ignoreRules {
+	f, err := os.Open(filepath.Join(dir, ignoreFileName))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules ignoreRules
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(base, scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreRule parses a line of a .mdfignore file. Blank lines and
+// comments are not rules.
+func parseIgnoreRule(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	// a pattern with a slash at the start or in the middle is relative to
+	// the .mdfignore directory, otherwise it matches a name at any depth
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// match reports whether the rule matches the slash separated path relative to
+// the repo root.
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = strings.TrimPrefix(rel, r.base+"/")
+	}
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], path.Base(rel))
+		return ok
+	}
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments matches the path segments against the pattern segments, where
+// ** matches any number of segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return len(segments) > 0
+			}
+			for i := range segments {
+				if matchSegments(pattern, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// ignored reports whether the path relative to the repo root is ignored, the
+// last matching rule wins.
+func (rules ignoreRules) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.match(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// includeExtension reports whether the file extension is one of the include
+// extensions. An empty list includes every file.
+func includeExtension(extensions []string, name string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+	for _, e := range extensions {
+		if strings.EqualFold(strings.TrimPrefix(e, "."), ext) && ext != "" {
+			return true
+		}
+	}
+	return false
+}
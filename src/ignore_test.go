@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestIgnoreRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		lines   []string
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{name: "name at any depth", lines: []string{"draft.md"}, path: "k8s/net/draft.md", ignored: true},
+		{name: "glob at any depth", lines: []string{"*.tmp.md"}, path: "k8s/pods.tmp.md", ignored: true},
+		{name: "no match", lines: []string{"draft.md"}, path: "k8s/pods.md"},
+		{name: "comment", lines: []string{"# draft.md"}, path: "draft.md"},
+		{name: "escaped hash", lines: []string{`\#draft.md`}, path: "#draft.md", ignored: true},
+		{name: "leading slash anchors", lines: []string{"/draft.md"}, path: "draft.md", ignored: true},
+		{name: "leading slash not nested", lines: []string{"/draft.md"}, path: "k8s/draft.md"},
+		{name: "middle slash anchors", lines: []string{"k8s/draft.md"}, path: "k8s/draft.md", ignored: true},
+		{name: "middle slash not nested", lines: []string{"k8s/draft.md"}, path: "cloud/k8s/draft.md"},
+		{name: "star stays in a segment", lines: []string{"k8s/*.md"}, path: "k8s/net/pods.md"},
+		{name: "leading double star", lines: []string{"**/drafts"}, path: "a/b/drafts", isDir: true, ignored: true},
+		{name: "leading double star at root", lines: []string{"**/drafts"}, path: "drafts", isDir: true, ignored: true},
+		{name: "middle double star", lines: []string{"k8s/**/draft.md"}, path: "k8s/a/b/draft.md", ignored: true},
+		{name: "middle double star no dir", lines: []string{"k8s/**/draft.md"}, path: "k8s/draft.md", ignored: true},
+		{name: "trailing double star", lines: []string{"k8s/**"}, path: "k8s/net/pods.md", ignored: true},
+		{name: "trailing double star not the dir", lines: []string{"k8s/**"}, path: "k8s", isDir: true},
+		{name: "dir only matches a dir", lines: []string{"drafts/"}, path: "k8s/drafts", isDir: true, ignored: true},
+		{name: "dir only skips a file", lines: []string{"drafts/"}, path: "k8s/drafts"},
+		{name: "negation", lines: []string{"*.md", "!keep.md"}, path: "k8s/keep.md"},
+		{name: "negation keeps others", lines: []string{"*.md", "!keep.md"}, path: "k8s/pods.md", ignored: true},
+		{name: "last rule wins", lines: []string{"!keep.md", "*.md"}, path: "keep.md", ignored: true},
+		{name: "escaped bang", lines: []string{`\!keep.md`}, path: "!keep.md", ignored: true},
+		{name: "relative to base", base: "k8s", lines: []string{"/draft.md"}, path: "k8s/draft.md", ignored: true},
+		{name: "outside of base", base: "k8s", lines: []string{"draft.md"}, path: "draft.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules ignoreRules
+			for _, line := range tt.lines {
+				if rule, ok := parseIgnoreRule(tt.base, line); ok {
+					rules = append(rules, rule)
+				}
+			}
+			if got := rules.ignored(tt.path, tt.isDir); got != tt.ignored {
+				t.Errorf("rules %q ignored(%q, %v) = %v, want %v", tt.lines, tt.path, tt.isDir, got, tt.ignored)
+			}
+		})
+	}
+}
+
+func TestIncludeExtension(t *testing.T) {
+	tests := []struct {
+		extensions []string
+		name       string
+		want       bool
+	}{
+		{nil, "notes.txt", true},
+		{nil, "Makefile", true},
+		{[]string{"md", "markdown"}, "pods.md", true},
+		{[]string{".md"}, "pods.MD", true},
+		{[]string{"md"}, "notes.txt", false},
+		{[]string{"md"}, "Makefile", false},
+	}
+	for _, tt := range tests {
+		if got := includeExtension(tt.extensions, tt.name); got != tt.want {
+			t.Errorf("includeExtension(%q, %q) = %v, want %v", tt.extensions, tt.name, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,18 @@ type KeyMap struct {
 	CopyContent       key.Binding
 	CopyContentExit   key.Binding
 	EditSnippet       key.Binding
+	RunBlock          key.Binding
+	NewSnippet        key.Binding
+	RenameSnippet     key.Binding
+	DeleteSnippet     key.Binding
+	PickTag           key.Binding
+	SortFrecent       key.Binding
+	SwitchRepo        key.Binding
+	CopyHistory       key.Binding
+	BlockCursor       key.Binding
+	Find              key.Binding
+	Hint              key.Binding
+	CopyLink          key.Binding
 	NextPane          key.Binding
 	PrevPane          key.Binding
 	ToggleSnippetPane key.Binding
@@ -31,10 +43,13 @@ func (k KeyMap) ShortHelp() []key.Binding {
 // FullHelp returns all help options in a more detailed view.
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.CopyContent, k.EditSnippet},
+		{k.CopyContent, k.Hint, k.CopyHistory, k.BlockCursor, k.CopyLink},
+		{k.EditSnippet, k.RunBlock},
 		{k.MoveSnippetDown, k.MoveSnippetUp},
+		{k.NewSnippet, k.RenameSnippet, k.DeleteSnippet},
+		{k.PickTag, k.SortFrecent, k.SwitchRepo},
 		{k.NextPane, k.PrevPane},
-		{k.Search, k.ToggleSnippetPane},
+		{k.Search, k.Find, k.ToggleSnippetPane},
 		{k.ToggleHelp, k.Quit},
 	}
 }
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	linkScheme = "mdf://"
+
+	openUsage = "Usage: mdf open mdf://<repo>/<folder>/<file>#<section>"
+
+	defaultSectionSlug = "section"
+)
+
+// sectionSlug returns the lowercase letters and digits of the title, the
+// words joined by "-". "List Files" becomes list-files.
+func sectionSlug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = true
+			continue
+		}
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// setSectionSlugs sets the slugs of the sections of a snippet. A repeated
+// title gets a -1, -2 suffix, skipping the suffixes taken by other titles
+// such as "Foo 1", so every section of the snippet has its own slug.
+func setSectionSlugs(sections []Section) {
+	used := make(map[string]bool)
+	next := make(map[string]int)
+	for i := range sections {
+		base := sectionSlug(sections[i].Title)
+		if base == "" {
+			base = defaultSectionSlug
+		}
+		slug := base
+		for used[slug] {
+			next[base]++
+			slug = base + "-" + strconv.Itoa(next[base])
+		}
+		used[slug] = true
+		sections[i].Slug = slug
+	}
+}
+
+// snippetLink returns the mdf://<repo>/<folder>/<file> URI of the snippet,
+// with the #<slug> of the section if any.
+func snippetLink(config Config, snippet Snippet, section Section) string {
+	repo := snippet.Repo
+	if repo == "" {
+		repo = config.getRepoName()
+	}
+	link := url.URL{Path: filepath.ToSlash(filepath.Join(repo, snippet.Path())), Fragment: section.Slug}
+	uri := linkScheme + link.EscapedPath()
+	if section.Slug != "" {
+		uri += "#" + link.EscapedFragment()
+	}
+	return uri
+}
+
+// linkTarget is the snippet and section a link points to, and the config of
+// its repo.
+type linkTarget struct {
+	config   Config
+	snippets []Snippet
+	snippet  Snippet
+	section  Section
+}
+
+// resolveLink finds the repo, snippet and section of an mdf:// URI. The
+// longest repo name the path starts with is the repo, the rest is the path
+// of the snippet.
+func resolveLink(config Config, uri string) (linkTarget, error) {
+	if !strings.HasPrefix(uri, linkScheme) {
+		return linkTarget{}, errors.New(openUsage)
+	}
+	rawPath, rawFragment, _ := strings.Cut(strings.TrimPrefix(uri, linkScheme), "#")
+	path, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return linkTarget{}, fmt.Errorf("invalid link %s: %w", uri, err)
+	}
+	fragment, err := url.PathUnescape(rawFragment)
+	if err != nil {
+		return linkTarget{}, fmt.Errorf("invalid link %s: %w", uri, err)
+	}
+
+	repos, err := readRepos(config)
+	if err != nil {
+		return linkTarget{}, fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+	repo := ""
+	for _, r := range repos {
+		if strings.HasPrefix(path, r.Name+"/") && len(r.Name) > len(repo) {
+			repo = r.Name
+		}
+	}
+	if repo == "" {
+		return linkTarget{}, fmt.Errorf("no repo matches %s", path)
+	}
+
+	target := linkTarget{config: config.forRepo(repo)}
+	target.snippets = loadSnippets(target.config)
+	snippetPath := filepath.FromSlash(strings.TrimPrefix(path, repo+"/"))
+	for _, snippet := range target.snippets {
+		if snippet.Path() == snippetPath {
+			target.snippet = snippet
+			break
+		}
+	}
+	if target.snippet.File == "" {
+		return linkTarget{}, fmt.Errorf("no snippet %s in repo %s", snippetPath, repo)
+	}
+
+	if fragment != "" {
+		sections, err := readSections(target.config, target.snippet)
+		if err != nil {
+			return linkTarget{}, fmt.Errorf("failed to read snippet: %w", err)
+		}
+		// a link names the section by its slug, it never falls back to a
+		// fuzzy match of another section
+		for _, section := range sections {
+			if section.Slug == fragment {
+				target.section = section
+				break
+			}
+		}
+		if target.section.Slug == "" {
+			return linkTarget{}, fmt.Errorf("no section %q in %s", fragment, target.snippet.Path())
+		}
+	}
+	return target, nil
+}
+
+// findTarget finds the snippet and the section of a <snippet>[#<section>]
+// query. The section is empty if none matches.
+func findTarget(config Config, snippets []Snippet, query string) (Snippet, Section) {
+	snippetQuery, sectionQuery := splitTarget(query)
+	snippet := findSnippet(snippetQuery, snippets)
+	if snippet.File == "" || sectionQuery == "" {
+		return snippet, Section{}
+	}
+	sections, err := readSections(config, snippet)
+	if err != nil {
+		return snippet, Section{}
+	}
+	section, _ := findSection(sectionQuery, snippet, sections)
+	return snippet, section
+}
+
+// selectSection selects the section with the slug in the section list.
+func (m *Model) selectSection(slug string) {
+	sections := m.Sections()
+	for i, item := range sections.Items() {
+		if item.(Section).Slug == slug {
+			sections.Select(i)
+			return
+		}
+	}
+}
+
+// copyLink copies the link of the selected section, or of the selected
+// snippet in the snippet pane.
+func (m *Model) copyLink() tea.Cmd {
+	var section Section
+	if m.pane != snippetPane {
+		section = m.selectedSection()
+	}
+	if err := m.setClipboard(snippetLink(m.config, m.selectedSnippet(), section)); err != nil {
+		return m.showError(err)
+	}
+	return m.copied(false)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSectionSlug(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"List Files", "list-files"},
+		{"  Pods & Logs!  ", "pods-logs"},
+		{"kubectl get -o yaml", "kubectl-get-o-yaml"},
+		{"Überblick 2", "überblick-2"},
+		{"***", ""},
+	}
+	for _, tt := range tests {
+		if got := sectionSlug(tt.title); got != tt.want {
+			t.Errorf("sectionSlug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestSetSectionSlugs(t *testing.T) {
+	tests := []struct {
+		titles []string
+		want   []string
+	}{
+		{[]string{"Pods", "Logs"}, []string{"pods", "logs"}},
+		{[]string{"Foo", "Foo", "Foo"}, []string{"foo", "foo-1", "foo-2"}},
+		{[]string{"Foo", "Foo", "Foo 1"}, []string{"foo", "foo-1", "foo-1-1"}},
+		{[]string{"Foo 1", "Foo", "Foo"}, []string{"foo-1", "foo", "foo-2"}},
+		{[]string{"", "!!", "Section"}, []string{"section", "section-1", "section-2"}},
+	}
+	for _, tt := range tests {
+		sections := make([]Section, len(tt.titles))
+		for i, title := range tt.titles {
+			sections[i].Title = title
+		}
+		setSectionSlugs(sections)
+		got := make([]string, len(sections))
+		for i, section := range sections {
+			got[i] = section.Slug
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("setSectionSlugs(%q) = %q, want %q", tt.titles, got, tt.want)
+		}
+	}
+}
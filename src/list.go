@@ -0,0 +1,404 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const listUsage = "Usage: mdf list repo|folder|snippet|section|block|tag [<snippet>[#<section>]] [--tag <tag>] [--json|--format text|json|tsv]"
+
+// listFormat is the output format of the list commands.
+type listFormat int
+
+const (
+	listFormatText listFormat = iota
+	listFormatJSON
+	listFormatTSV
+)
+
+// repoRecord is the machine-readable form of a Repo.
+type repoRecord struct {
+	Name    string `json:"name"`
+	Url     string `json:"url"`
+	Path    string `json:"path"`
+	Current bool   `json:"current"`
+}
+
+// folderRecord is the machine-readable form of a Folder.
+type folderRecord struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Snippets int    `json:"snippets"`
+	Current  bool   `json:"current"`
+}
+
+// snippetRecord is the machine-readable form of a Snippet.
+type snippetRecord struct {
+	Repo        string    `json:"repo"`
+	Folder      string    `json:"folder"`
+	Name        string    `json:"name"`
+	File        string    `json:"file"`
+	Path        string    `json:"path"`
+	Language    string    `json:"language"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Tags        []string  `json:"tags"`
+	Aliases     []string  `json:"aliases"`
+}
+
+// sectionRecord is the machine-readable form of a Section. Index is 1-based.
+type sectionRecord struct {
+	Repo       string   `json:"repo"`
+	Folder     string   `json:"folder"`
+	File       string   `json:"file"`
+	Path       string   `json:"path"`
+	Index      int      `json:"index"`
+	Title      string   `json:"title"`
+	Slug       string   `json:"slug"`
+	Link       string   `json:"link"`
+	CodeBlocks int      `json:"code_blocks"`
+	Tags       []string `json:"tags"`
+}
+
+// blockRecord is the machine-readable form of a CodeBlock. Index is 1-based
+// and matches the --block flag of mdf copy.
+type blockRecord struct {
+	Repo         string            `json:"repo"`
+	Folder       string            `json:"folder"`
+	File         string            `json:"file"`
+	Path         string            `json:"path"`
+	Section      string            `json:"section"`
+	SectionIndex int               `json:"section_index"`
+	Index        int               `json:"index"`
+	Language     string            `json:"language"`
+	Meta         map[string]string `json:"meta"`
+	Content      string            `json:"content"`
+}
+
+// runList prints repos, folders, snippets, sections, code blocks or tags.
+// The --tag flag lists the snippets with the tag only.
+func runList(config Config, snippets []Snippet, args []string) error {
+	parsed := parseArgs(args, "format", "tag")
+	format, err := parseListFormat(parsed)
+	if err != nil {
+		return err
+	}
+	if tag := parsed.get("tag"); tag != "" {
+		snippets = filterSnippetsByTag(snippets, tag)
+	}
+
+	kind := parsed.arg(0)
+	query := parsed.arg(1)
+	switch {
+	case strings.Contains(kind, "repo"):
+		if format == listFormatText {
+			return listRepos(config)
+		}
+		return listRepoRecords(config, format)
+	case strings.Contains(kind, "folder"):
+		if format == listFormatText {
+			return listFolders(config, snippets)
+		}
+		return listFolderRecords(config, snippets, format)
+	case strings.Contains(kind, "snippet"):
+		if format == listFormatText {
+			listSnippets(snippets)
+			return nil
+		}
+		return listSnippetRecords(config, snippets, format)
+	case strings.Contains(kind, "section"):
+		return listSectionRecords(config, snippets, query, format)
+	case strings.Contains(kind, "block"):
+		return listBlockRecords(config, snippets, query, format)
+	case strings.Contains(kind, "tag"):
+		if format == listFormatText {
+			listTags(snippets)
+			return nil
+		}
+		return listTagRecords(snippets, format)
+	}
+	return errors.New(listUsage)
+}
+
+// parseListFormat returns the output format from the --json and --format
+// flags.
+func parseListFormat(parsed cliArgs) (listFormat, error) {
+	if parsed.has("json") {
+		return listFormatJSON, nil
+	}
+	switch parsed.get("format") {
+	case "", "text":
+		return listFormatText, nil
+	case "json":
+		return listFormatJSON, nil
+	case "tsv":
+		return listFormatTSV, nil
+	}
+	return listFormatText, fmt.Errorf("unknown format %q, use text, json or tsv", parsed.get("format"))
+}
+
+func listRepoRecords(config Config, format listFormat) error {
+	repos, err := readRepos(config)
+	if err != nil {
+		return fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+
+	return writeRecords(os.Stdout, format, newRepoRecords(config, repos),
+		[]string{"name", "url", "path", "current"},
+		func(r repoRecord) []string {
+			return []string{r.Name, r.Url, r.Path, strconv.FormatBool(r.Current)}
+		})
+}
+
+// newRepoRecords returns the records of the repos. Only the configured repo
+// is current, --all loads every repo but doesn't switch to any of them.
+func newRepoRecords(config Config, repos []Repo) []repoRecord {
+	records := make([]repoRecord, 0, len(repos))
+	for _, repo := range repos {
+		records = append(records, repoRecord{
+			Name:    repo.Name,
+			Url:     repo.Url,
+			Path:    absPath(config.forRepo(repo.Name).getRepoPath()),
+			Current: repo.Name == config.RepoName,
+		})
+	}
+	return records
+}
+
+func listFolderRecords(config Config, snippets []Snippet, format listFormat) error {
+	counts := make(map[string]int)
+	paths := make(map[string]string)
+	for _, snippet := range snippets {
+		counts[snippet.Folder]++
+		if _, ok := paths[snippet.Folder]; !ok {
+			paths[snippet.Folder] = filepath.Join(config.getSnippetRepoPath(snippet), snippet.Folder)
+		}
+	}
+
+	folders := getFolders(snippets)
+	records := make([]folderRecord, 0, len(folders))
+	for _, folder := range folders {
+		records = append(records, folderRecord{
+			Name:     folder,
+			Path:     absPath(paths[folder]),
+			Snippets: counts[folder],
+			Current:  folder == config.FolderName,
+		})
+	}
+
+	return writeRecords(os.Stdout, format, records,
+		[]string{"name", "path", "snippets", "current"},
+		func(r folderRecord) []string {
+			return []string{r.Name, r.Path, strconv.Itoa(r.Snippets), strconv.FormatBool(r.Current)}
+		})
+}
+
+func listSnippetRecords(config Config, snippets []Snippet, format listFormat) error {
+	records := make([]snippetRecord, 0, len(snippets))
+	for _, snippet := range snippets {
+		records = append(records, newSnippetRecord(config, snippet))
+	}
+
+	return writeRecords(os.Stdout, format, records,
+		[]string{"repo", "folder", "name", "file", "path", "language", "date", "description", "tags", "aliases"},
+		func(r snippetRecord) []string {
+			return []string{r.Repo, r.Folder, r.Name, r.File, r.Path, r.Language, r.Date.Format(time.RFC3339),
+				r.Description, strings.Join(r.Tags, ","), strings.Join(r.Aliases, ",")}
+		})
+}
+
+func newSnippetRecord(config Config, snippet Snippet) snippetRecord {
+	repo := snippet.Repo
+	if repo == "" {
+		repo = config.getRepoName()
+	}
+	return snippetRecord{
+		Repo:        repo,
+		Folder:      snippet.Folder,
+		Name:        snippet.Name,
+		File:        snippet.File,
+		Path:        absPath(config.getSnippetPath(snippet)),
+		Language:    snippet.Language,
+		Date:        snippet.Date,
+		Description: snippet.Description,
+		Tags:        append([]string{}, snippet.Tags...),
+		Aliases:     append([]string{}, snippet.Aliases...),
+	}
+}
+
+func listSectionRecords(config Config, snippets []Snippet, query string, format listFormat) error {
+	var records []sectionRecord
+	err := walkSections(config, snippets, query, func(snippet Snippet, index int, section Section) {
+		s := newSnippetRecord(config, snippet)
+		records = append(records, sectionRecord{
+			Repo:       s.Repo,
+			Folder:     s.Folder,
+			File:       s.File,
+			Path:       s.Path,
+			Index:      index + 1,
+			Title:      section.Title,
+			Slug:       section.Slug,
+			Link:       snippetLink(config, snippet, section),
+			CodeBlocks: len(section.CodeBlocks),
+			Tags:       append([]string{}, section.Tags...),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == listFormatText {
+		for _, r := range records {
+			fmt.Printf("%s/%s#%s\n", r.Folder, r.File, r.Title)
+		}
+		return nil
+	}
+	return writeRecords(os.Stdout, format, records,
+		[]string{"repo", "folder", "file", "path", "index", "title", "slug", "link", "code_blocks", "tags"},
+		func(r sectionRecord) []string {
+			return []string{r.Repo, r.Folder, r.File, r.Path, strconv.Itoa(r.Index), r.Title, r.Slug, r.Link,
+				strconv.Itoa(r.CodeBlocks), strings.Join(r.Tags, ",")}
+		})
+}
+
+func listBlockRecords(config Config, snippets []Snippet, query string, format listFormat) error {
+	var records []blockRecord
+	err := walkSections(config, snippets, query, func(snippet Snippet, index int, section Section) {
+		s := newSnippetRecord(config, snippet)
+		for i, codeBlock := range section.CodeBlocks {
+			records = append(records, blockRecord{
+				Repo:         s.Repo,
+				Folder:       s.Folder,
+				File:         s.File,
+				Path:         s.Path,
+				Section:      section.Title,
+				SectionIndex: index + 1,
+				Index:        i + 1,
+				Language:     codeBlock.Language,
+				Meta:         codeBlock.Meta,
+				Content:      codeBlock.Content,
+			})
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if format == listFormatText {
+		for _, r := range records {
+			fmt.Printf("%s/%s#%s [%d] %s\n", r.Folder, r.File, r.Section, r.Index, r.Language)
+		}
+		return nil
+	}
+	return writeRecords(os.Stdout, format, records,
+		[]string{"repo", "folder", "file", "path", "section", "section_index", "index", "language", "meta", "content"},
+		func(r blockRecord) []string {
+			return []string{r.Repo, r.Folder, r.File, r.Path, r.Section, strconv.Itoa(r.SectionIndex),
+				strconv.Itoa(r.Index), r.Language, formatMeta(r.Meta), r.Content}
+		})
+}
+
+// walkSections calls fn for every section of the snippets. The optional
+// <snippet>[#<section>] query narrows the walk to one snippet or section.
+func walkSections(config Config, snippets []Snippet, query string, fn func(Snippet, int, Section)) error {
+	snippetQuery, sectionQuery := splitTarget(query)
+	if snippetQuery != "" {
+		snippet := findSnippet(snippetQuery, snippets)
+		if snippet.File == "" {
+			return fmt.Errorf("no snippet matches %q", snippetQuery)
+		}
+		snippets = []Snippet{snippet}
+	}
+
+	for _, snippet := range snippets {
+		sections, err := readSections(config, snippet)
+		if err != nil {
+			return fmt.Errorf("failed to read snippet %s: %w", snippet.Path(), err)
+		}
+		if sectionQuery == "" {
+			for i, section := range sections {
+				fn(snippet, i, section)
+			}
+			continue
+		}
+		found, ok := findSection(sectionQuery, snippet, sections)
+		if !ok {
+			return fmt.Errorf("no section of %s matches %q", snippet.Path(), sectionQuery)
+		}
+		// slugs are unique in a snippet, titles may repeat
+		for i, section := range sections {
+			if section.Slug == found.Slug {
+				fn(snippet, i, section)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// writeRecords writes the records as an indented JSON array or as TSV with a
+// header row.
+func writeRecords[T any](w io.Writer, format listFormat, records []T, header []string, row func(T) []string) error {
+	if format == listFormatJSON {
+		if records == nil {
+			records = []T{}
+		}
+		b, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal records: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, record := range records {
+		fields := row(record)
+		for i, field := range fields {
+			fields[i] = escapeTSV(field)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeTSV escapes backslashes, tabs and newlines of a TSV field.
+func escapeTSV(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// formatMeta formats the code block meta as sorted key=value pairs.
+func formatMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+meta[k])
+	}
+	return strings.Join(pairs, ";")
+}
+
+// absPath returns the absolute path, or the path itself if it can't be
+// resolved.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewRepoRecords(t *testing.T) {
+	repos := []Repo{{Name: "kugarocks/rockman"}, {Name: "team/notes"}}
+	tests := []struct {
+		name     string
+		repoName string
+		allRepos bool
+		want     []bool
+	}{
+		{"configured repo", "team/notes", false, []bool{false, true}},
+		{"all repos flag", "team/notes", true, []bool{false, true}},
+		{"all repos name", allReposName, false, []bool{false, false}},
+	}
+	for _, tt := range tests {
+		config := Config{Home: t.TempDir(), RepoName: tt.repoName, AllRepos: tt.allRepos}
+		records := newRepoRecords(config, repos)
+		for i, record := range records {
+			if record.Current != tt.want[i] {
+				t.Errorf("%s: %s current = %v, want %v", tt.name, record.Name, record.Current, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestWriteRecords(t *testing.T) {
+	type record struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	row := func(r record) []string {
+		return []string{r.Name, strconv.Itoa(r.Count)}
+	}
+	tests := []struct {
+		name    string
+		format  listFormat
+		records []record
+		want    string
+	}{
+		{
+			name:    "json",
+			format:  listFormatJSON,
+			records: []record{{"pods", 2}},
+			want:    "[\n  {\n    \"name\": \"pods\",\n    \"count\": 2\n  }\n]\n",
+		},
+		{
+			name:   "json without records",
+			format: listFormatJSON,
+			want:   "[]\n",
+		},
+		{
+			name:    "tsv",
+			format:  listFormatTSV,
+			records: []record{{"pods", 2}, {"a\tb\nc", 0}},
+			want:    "name\tcount\npods\t2\na\\tb\\nc\t0\n",
+		},
+		{
+			name:   "tsv without records",
+			format: listFormatTSV,
+			want:   "name\tcount\n",
+		},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := writeRecords(&b, tt.format, tt.records, []string{"name", "count"}, row); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("%s: writeRecords = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeTSV(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"pods", "pods"},
+		{"a\tb", `a\tb`},
+		{"a\r\nb", `a\r\nb`},
+		{`C:\tmp`, `C:\\tmp`},
+		{"\\\t", `\\\t`},
+	}
+	for _, tt := range tests {
+		if got := escapeTSV(tt.field); got != tt.want {
+			t.Errorf("escapeTSV(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
@@ -23,10 +24,12 @@ import (
 )
 
 const (
-	Version           = "v1.2.0"
-	githubSSHPrefix   = "git@github.com:"
-	githubHTTPSPrefix = "https://github.com/"
-	githubSSHSuffix   = ".git"
+	Version         = "v1.2.0"
+	githubSSHPrefix = "git@github.com:"
+	githubSSHSuffix = ".git"
+
+	getRepoUsage = "Usage: mdf get repo <user/repo>|<git url> [--name <name>]"
+	setUsage     = "Usage: mdf set repo|folder"
 )
 
 func main() {
@@ -36,41 +39,88 @@ func main() {
 func runCLI(args []string) {
 	config := readConfig()
 
+	// --all loads the snippets of every repo for this run only
+	if i := slices.Index(args, "--all"); i >= 0 {
+		config.AllRepos = true
+		args = slices.Delete(args, i, i+1)
+	}
+
 	err := initDefaultRepo(config)
 	if err != nil {
 		fmt.Println("Init default repo failed", err)
 		return
 	}
+	if repos, err := readRepos(config); err == nil {
+		reportMissingRepos(config, repos)
+	}
 
 	validateRepoName(&config)
-	snippets := readSnippets(config)
-	snippets = scanSnippets(config, snippets)
+	snippets := loadSnippets(config)
 
 	initFolderName(&config, snippets)
 
 	var targetSnippet Snippet
+	var targetSection Section
 	if len(args) > 1 {
 		switch args[0] {
 		case "list":
-			if strings.Contains(args[1], "repo") {
-				if err = listRepos(config); err != nil {
-					fmt.Println(err)
-				}
-				return
-			} else if strings.Contains(args[1], "folder") {
-				if err = listFolders(config, snippets); err != nil {
-					fmt.Println(err)
-				}
-				return
-			} else if strings.Contains(args[1], "snippet") {
-				listSnippets(snippets)
+			if err = runList(config, snippets, args[1:]); err != nil {
+				fmt.Println(err)
+			}
+			return
+		case "copy":
+			if err = runCopy(config, snippets, args[1:]); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		case "search":
+			if err = runSearch(config, snippets, args[1:]); err != nil {
+				fmt.Println(err)
+			}
+			return
+		case "history":
+			if err = runHistory(config, args[1:]); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		case "open":
+			target, err := resolveLink(config, args[1])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			config, snippets = target.config, target.snippets
+			targetSnippet, targetSection = target.snippet, target.section
+		case "new":
+			if err = runNew(config, snippets, args[1:]); err != nil {
+				fmt.Println(err)
+			}
+			return
+		case "rm":
+			if err = runRemove(config, snippets, args[1:]); err != nil {
+				fmt.Println(err)
+			}
+			return
+		case "mv":
+			if err = runMove(config, snippets, args[1:]); err != nil {
+				fmt.Println(err)
 			}
+			return
+		case "repo":
+			if err = runRepo(config, args[1:]); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
 		case "get":
-			if len(args) < 3 || args[1] != "repo" {
-				fmt.Println("Usage: mdf get repo <user/repo>")
+			parsed := parseArgs(args[1:], "name")
+			if parsed.arg(0) != "repo" || parsed.arg(1) == "" {
+				fmt.Println(getRepoUsage)
 				return
 			}
-			err = getRepo(config, args[2])
+			err = getRepo(config, parsed.arg(1), parsed.get("name"))
 			if err != nil {
 				fmt.Printf("Failed to get repo: %v\n", err)
 			}
@@ -87,10 +137,12 @@ func runCLI(args []string) {
 				}
 				return
 			}
+			fmt.Println(setUsage)
+			return
 		default:
 			fmt.Println("Unknown command")
+			return
 		}
-		return
 	} else if len(args) == 1 {
 		switch args[0] {
 		case "-h", "--help":
@@ -99,12 +151,51 @@ func runCLI(args []string) {
 		case "-v", "--version", "version":
 			fmt.Println(Version)
 			return
+		case "copy":
+			fmt.Println(copyUsage)
+			return
+		case "repo":
+			fmt.Println(repoUsage)
+			return
+		case "search":
+			if err = runSearch(config, snippets, nil); err != nil {
+				fmt.Println(err)
+			}
+			return
+		case "history":
+			if err = runHistory(config, nil); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		case "open":
+			fmt.Println(openUsage)
+			return
+		case "new":
+			fmt.Println(newUsage)
+			return
+		case "rm":
+			fmt.Println(removeUsage)
+			return
+		case "mv":
+			fmt.Println(moveUsage)
+			return
 		default:
-			targetSnippet = findSnippet(args[0], snippets)
+			if strings.HasPrefix(args[0], linkScheme) {
+				target, err := resolveLink(config, args[0])
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				config, snippets = target.config, target.snippets
+				targetSnippet, targetSection = target.snippet, target.section
+				break
+			}
+			targetSnippet, targetSection = findTarget(config, snippets, args[0])
 		}
 	}
 
-	err = runInteractiveMode(config, snippets, targetSnippet)
+	err = runInteractiveMode(config, snippets, targetSnippet, targetSection)
 	if err != nil {
 		fmt.Println("Alas, there's been an error", err)
 	}
@@ -140,6 +231,36 @@ func readSnippets(config Config) []Snippet {
 	return wrapper.SnippetList
 }
 
+// loadSnippets reads and scans the snippets of the configured repo, or of
+// every repo in repo-config.json when all repos are loaded.
+func loadSnippets(config Config) []Snippet {
+	if !config.isAllRepos() {
+		snippets := scanSnippets(config, readSnippets(config))
+		applyUsage(config, snippets)
+		return snippets
+	}
+
+	repos, err := readRepos(config)
+	if err != nil {
+		fmt.Printf("Unable to read repos, %+v\n", err)
+		return nil
+	}
+
+	var snippets []Snippet
+	for _, repo := range repos {
+		repoConfig := config.forRepo(repo.Name)
+		if _, err = os.Stat(repoConfig.getRepoPath()); err != nil {
+			continue
+		}
+		for _, snippet := range scanSnippets(repoConfig, readSnippets(repoConfig)) {
+			snippet.Repo = repo.Name
+			snippets = append(snippets, snippet)
+		}
+	}
+	applyUsage(config, snippets)
+	return snippets
+}
+
 // scanSnippets scans for any new/removed snippets and adds them to snippet-config.json
 func scanSnippets(config Config, snippets []Snippet) []Snippet {
 	var modified bool
@@ -153,54 +274,100 @@ func scanSnippets(config Config, snippets []Snippet) []Snippet {
 	}
 
 	repoPath := config.getRepoPath()
-	homeEntries, err := os.ReadDir(repoPath)
-	if err != nil {
-		fmt.Printf("could not scan config home: %v\n", err)
-		return snippets
-	}
-
-	for _, homeEntry := range homeEntries {
-		if !homeEntry.IsDir() {
-			continue
+	// the files not modified since the snippets file was written keep their
+	// front matter
+	var indexTime time.Time
+	if info, err := os.Stat(filepath.Join(repoPath, config.SnippetConfigFile)); err == nil {
+		indexTime = info.ModTime()
+	}
+	// the .mdfignore rules of every scanned directory, including its parents
+	dirRules := map[string]ignoreRules{".": readIgnoreFile(repoPath, "")}
+	// the modification time of the scanned files
+	scanned := make(map[string]time.Time)
+	err := filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == repoPath {
+				return err
+			}
+			fmt.Printf("could not scan %q: %v\n", path, err)
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
-		if strings.HasPrefix(homeEntry.Name(), ".") {
-			continue
+		if path == repoPath {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
 
-		folderPath := filepath.Join(repoPath, homeEntry.Name())
-		folderEntries, err := os.ReadDir(folderPath)
+		snippetPath, err := filepath.Rel(repoPath, path)
 		if err != nil {
-			fmt.Printf("could not scan %q: %v\n", folderPath, err)
-			continue
+			return nil
 		}
-
-		for _, folderEntry := range folderEntries {
-			if folderEntry.IsDir() {
-				continue
+		rel := filepath.ToSlash(snippetPath)
+		folder := filepath.ToSlash(filepath.Dir(snippetPath))
+		rules := dirRules[folder]
+		if rules.ignored(rel, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
+		}
+		if entry.IsDir() {
+			dirRules[rel] = append(rules[:len(rules):len(rules)], readIgnoreFile(path, rel)...)
+			return nil
+		}
+		// files in the repo root, like README.md, are not snippets
+		if folder == "." || !includeExtension(config.IncludeExtensions, entry.Name()) {
+			return nil
+		}
 
-			snippetPath := filepath.Join(homeEntry.Name(), folderEntry.Name())
-			if !snippetExists(snippetPath) {
-				name := folderEntry.Name()
-				ext := filepath.Ext(name)
-				snippets = append(snippets, Snippet{
-					Folder:   homeEntry.Name(),
-					Date:     time.Now(),
-					Name:     strings.TrimSuffix(name, ext),
-					File:     name,
-					Language: strings.TrimPrefix(ext, "."),
-				})
-				modified = true
-			}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		scanned[snippetPath] = info.ModTime()
+		if !snippetExists(snippetPath) {
+			name := entry.Name()
+			ext := filepath.Ext(name)
+			snippets = append(snippets, Snippet{
+				Folder:   folder,
+				Date:     time.Now(),
+				Name:     strings.TrimSuffix(name, ext),
+				File:     name,
+				Language: strings.TrimPrefix(ext, "."),
+			})
+			// a new file is read even if it is older than the snippets file
+			scanned[snippetPath] = indexTime
+			modified = true
 		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("could not scan config home: %v\n", err)
+		return snippets
 	}
 
+	// drop the removed, ignored and filtered snippets, and refresh the front
+	// matter of the others
 	var idx int
 	for _, snippet := range snippets {
-		snippetPath := filepath.Join(repoPath, snippet.Path())
-		if _, err := os.Stat(snippetPath); !errors.Is(err, fs.ErrNotExist) {
+		if modTime, ok := scanned[snippet.Path()]; ok {
+			if !modTime.Before(indexTime) {
+				scannedSnippet := readFrontMatter(config, snippet)
+				if !reflect.DeepEqual(scannedSnippet, snippet) {
+					modified = true
+				}
+				snippet = scannedSnippet
+			}
 			snippets[idx] = snippet
 			idx++
+		} else {
 			modified = true
 		}
 	}
@@ -213,8 +380,24 @@ func scanSnippets(config Config, snippets []Snippet) []Snippet {
 	return snippets
 }
 
-// writeSnippets writes the snippets to the snippets file
+// writeSnippets writes the snippets to the snippets file, or to the snippets
+// file of their repo when all repos are loaded.
 func writeSnippets(config Config, snippets []Snippet) {
+	if config.isAllRepos() {
+		repoSnippets := make(map[string][]Snippet)
+		for _, snippet := range snippets {
+			repo := snippet.Repo
+			if repo == "" {
+				repo = config.getRepoName()
+			}
+			repoSnippets[repo] = append(repoSnippets[repo], snippet)
+		}
+		for repo, snippets := range repoSnippets {
+			writeSnippets(config.forRepo(repo), snippets)
+		}
+		return
+	}
+
 	wrapper := SnippetsWrapper{
 		SnippetList: snippets,
 	}
@@ -241,18 +424,56 @@ func listSnippets(snippets []Snippet) {
 
 func findSnippet(search string, snippets []Snippet) Snippet {
 	matches := fuzzy.FindFrom(search, Snippets{snippets})
-	if len(matches) > 0 {
-		return snippets[matches[0].Index]
+	if len(matches) == 0 {
+		return Snippet{}
 	}
-	return Snippet{}
+	now := time.Now()
+	return snippets[bestMatch(matches, func(i int) float64 {
+		return snippets[i].Usage.frecency(now)
+	})]
 }
 
-func runInteractiveMode(config Config, snippets []Snippet, targetSnippet Snippet) error {
+func runInteractiveMode(config Config, snippets []Snippet, targetSnippet Snippet, targetSection Section) error {
 	if len(snippets) == 0 {
 		// welcome to nap!
 		snippets = append(snippets, defaultSnippet)
 	}
+	if targetSnippet.File != "" {
+		recordSnippetUsage(config, snippets, targetSnippet, targetSection.Slug, false)
+	}
+
+	m := newModel(config, snippets, targetSnippet)
+	m.targetSection = targetSection.Slug
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	model, err := p.Run()
+	if err != nil {
+		return err
+	}
+	fm, ok := model.(*Model)
+	if !ok {
+		return err
+	}
+	fm.run.kill()
+	// the repo may have been switched in the TUI
+	writeSnippets(fm.config, fm.allSnippets())
+	writeUsage(fm.config, fm.allSnippets())
+	if fm.config.FolderName != config.FolderName {
+		if err = saveFolderName(fm.config.FolderName); err != nil {
+			return fmt.Errorf("write config failed: %w", err)
+		}
+	}
+	if fm.output != "" {
+		fmt.Println(fm.output)
+	}
+	return nil
+}
 
+// newModel returns the model with the snippets grouped by folder. The folder
+// and the snippet of the target are selected, the snippet pane is hidden when
+// a target snippet is found.
+func newModel(config Config, snippets []Snippet, targetSnippet Snippet) *Model {
+	// snippets with an order in the front matter come first
+	slices.SortStableFunc(snippets, compareSnippetOrder)
 	folders := make(map[Folder][]list.Item)
 	for _, snippet := range snippets {
 		folders[Folder(snippet.Folder)] = append(folders[Folder(snippet.Folder)], list.Item(snippet))
@@ -262,14 +483,16 @@ func runInteractiveMode(config Config, snippets []Snippet, targetSnippet Snippet
 
 	var folderItems []list.Item
 	foldersSlice := maps.Keys(folders)
-	slices.Sort(foldersSlice)
+	slices.SortFunc(foldersSlice, func(a, b Folder) int {
+		return compareFolders(string(a), string(b))
+	})
 	for _, folder := range foldersSlice {
 		folderItems = append(folderItems, list.Item(folder))
 	}
 	if len(folderItems) <= 0 {
 		folderItems = append(folderItems, list.Item(Folder(defaultSnippetFolder)))
 	}
-	folderList := list.New(folderItems, folderDelegate{defaultStyles.Folders.Blurred}, 0, 0)
+	folderList := newFolderList(folderItems, config, defaultStyles.Folders.Blurred)
 
 	for idx, folder := range foldersSlice {
 		if string(folder) == targetSnippet.Folder {
@@ -289,7 +512,7 @@ func runInteractiveMode(config Config, snippets []Snippet, targetSnippet Snippet
 		snippetsMap[folder] = snippetList
 		if folder == selectedFolder {
 			for idx, item := range snippetList.Items() {
-				if s, ok := item.(Snippet); ok && s.File == targetSnippet.File {
+				if s, ok := item.(Snippet); ok && s.key() == targetSnippet.key() {
 					snippetList.Select(idx)
 					hideSnippetPane = true
 					break
@@ -307,7 +530,7 @@ func runInteractiveMode(config Config, snippets []Snippet, targetSnippet Snippet
 	)
 
 	content := viewport.New(80, 0)
-	m := &Model{
+	return &Model{
 		SnippetsMap:     snippetsMap,
 		Folders:         folderList,
 		Code:            content,
@@ -316,34 +539,17 @@ func runInteractiveMode(config Config, snippets []Snippet, targetSnippet Snippet
 		mdRender:        mdRender,
 		hideSnippetPane: hideSnippetPane,
 	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	model, err := p.Run()
-	if err != nil {
-		return err
-	}
-	fm, ok := model.(*Model)
-	if !ok {
-		return err
-	}
-	var allSnippets []Snippet
-	for _, snippetList := range fm.SnippetsMap {
-		for _, item := range snippetList.Items() {
-			allSnippets = append(allSnippets, item.(Snippet))
-		}
-	}
-	writeSnippets(config, allSnippets)
-	return nil
 }
 
 func newList(items []list.Item, height int, styles SnippetsBaseStyle) *list.Model {
 	snippetList := list.New(items, snippetDelegate{snippetPane, styles, navigatingState}, 25, height)
 	snippetList.SetShowHelp(false)
 	snippetList.SetShowFilter(false)
+	// "/" opens the search overlay instead of the list filter
+	snippetList.SetFilteringEnabled(false)
 	snippetList.SetShowTitle(false)
 	snippetList.Styles.StatusBar = lipgloss.NewStyle().Margin(1, 3).Foreground(lipgloss.Color("240")).MaxWidth(35 - 2)
 	snippetList.Styles.NoItems = lipgloss.NewStyle().Margin(0, 3).Foreground(lipgloss.Color("8")).MaxWidth(35 - 2)
-	snippetList.FilterInput.Prompt = "Find: "
-	snippetList.FilterInput.PromptStyle = styles.Title
 	snippetList.SetStatusBarItemName("Snippet", "Snippets")
 	snippetList.DisableQuitKeybindings()
 	snippetList.Styles.Title = styles.Title
@@ -352,6 +558,20 @@ func newList(items []list.Item, height int, styles SnippetsBaseStyle) *list.Mode
 	return &snippetList
 }
 
+func newFolderList(items []list.Item, config Config, styles FoldersBaseStyle) list.Model {
+	folderList := list.New(items, folderDelegate{styles}, config.FolderTitleBarWidth+3, 20)
+	folderList.SetShowHelp(false)
+	folderList.SetShowFilter(false)
+	folderList.SetShowTitle(false)
+	folderList.SetFilteringEnabled(false)
+	folderList.Styles.StatusBar = lipgloss.NewStyle().Margin(1, 3).Foreground(lipgloss.Color("240")).MaxWidth(config.FolderTitleBarWidth)
+	folderList.Styles.NoItems = lipgloss.NewStyle().Margin(0, 3).Foreground(lipgloss.Color("8")).MaxWidth(config.FolderTitleBarWidth)
+	folderList.SetStatusBarItemName("Folder", "Folders")
+	folderList.DisableQuitKeybindings()
+
+	return folderList
+}
+
 func initDefaultRepo(config Config) error {
 	// Read existing repos
 	repos, err := readRepos(config)
@@ -396,59 +616,18 @@ func initDefaultRepo(config Config) error {
 	return nil
 }
 
-func parseGitHubURL(repoURL string) (user, repoName string, err error) {
-	// Supported formats:
-	// https://github.com/user/repo-name.git
-	// https://github.com/user/repo-name
-	// git@github.com:user/repo-name.git
-	// user/repo-name
-
-	// Remove .git suffix
-	repoURL = strings.TrimSuffix(repoURL, githubSSHSuffix)
-
-	// Handle SSH format
-	if strings.HasPrefix(repoURL, githubSSHPrefix) {
-		parts := strings.Split(strings.TrimPrefix(repoURL, githubSSHPrefix), "/")
-		if len(parts) != 2 {
-			return "", "", fmt.Errorf("invalid GitHub repository URL: %s", repoURL)
-		}
-		return parts[0], parts[1], nil
-	}
-
-	// Handle HTTPS format
-	if strings.HasPrefix(repoURL, githubHTTPSPrefix) {
-		parts := strings.Split(strings.TrimPrefix(repoURL, githubHTTPSPrefix), "/")
-		if len(parts) != 2 {
-			return "", "", fmt.Errorf("invalid GitHub repository URL: %s", repoURL)
-		}
-		return parts[0], parts[1], nil
-	}
-
-	// Handle short format user/repo
-	parts := strings.Split(repoURL, "/")
-	if len(parts) != 2 {
-		return "", "", fmt.Errorf("invalid GitHub repository URL: %s", repoURL)
-	}
-	return parts[0], parts[1], nil
-}
-
-func getRepo(config Config, repoURL string) error {
-	// Parse GitHub repository URL
-	user, repoName, err := parseGitHubURL(repoURL)
+func getRepo(config Config, repoURL, name string) error {
+	// Parse the remote URL
+	r, err := parseRemoteURL(repoURL)
 	if err != nil {
 		return err
 	}
-
-	// Determine the clone URL based on the input format
-	var cloneURL string
-	switch {
-	case strings.HasPrefix(repoURL, githubSSHPrefix):
-		cloneURL = repoURL
-	case strings.HasPrefix(repoURL, githubHTTPSPrefix):
-		cloneURL = repoURL
-	default:
-		cloneURL = fmt.Sprintf("git@github.com:%s/%s.git", user, repoName)
+	if name == "" {
+		name = r.Name()
+	} else if err = checkRepoName(name); err != nil {
+		return err
 	}
+	cloneURL := r.CloneURL
 
 	// Read existing repos
 	repos, err := readRepos(config)
@@ -457,15 +636,16 @@ func getRepo(config Config, repoURL string) error {
 	}
 
 	// Check if the repo already exists
-	userRepoName := fmt.Sprintf("%s/%s", user, repoName)
 	for _, repo := range repos {
-		if repo.Name == userRepoName {
-			return fmt.Errorf("repo %s already exists", userRepoName)
+		if repo.Name == name {
+			return fmt.Errorf("repo %s already exists", name)
 		}
 	}
 
-	// Create repo directory
-	repoPath := filepath.Join(config.getRepoBase(), userRepoName)
+	// Create repo directory, it is deleted again if the clone fails
+	repoPath := config.forRepo(name).getRepoPath()
+	_, err = os.Stat(repoPath)
+	created := errors.Is(err, fs.ErrNotExist)
 	err = os.MkdirAll(repoPath, os.ModePerm)
 	if err != nil {
 		return fmt.Errorf("failed to create repo directory: %w", err)
@@ -479,12 +659,15 @@ func getRepo(config Config, repoURL string) error {
 
 	err = cmd.Run()
 	if err != nil {
+		if created {
+			_ = deleteRepoDir(config, repoPath)
+		}
 		return fmt.Errorf("failed to clone repository: %w", err)
 	}
 
 	// Add new repo to the list
 	newRepo := Repo{
-		Name: userRepoName,
+		Name: name,
 		Url:  cloneURL,
 	}
 	repos = append(repos, newRepo)
@@ -495,7 +678,7 @@ func getRepo(config Config, repoURL string) error {
 		return fmt.Errorf("failed to save repo configuration: %w", err)
 	}
 
-	fmt.Printf("Successfully added repo: %s\n", userRepoName)
+	fmt.Printf("Successfully added repo: %s\n", name)
 	return nil
 }
 
@@ -518,7 +701,7 @@ func initFolderName(config *Config, snippets []Snippet) {
 	for folder := range folderSet {
 		folderNameList = append(folderNameList, folder)
 	}
-	slices.Sort(folderNameList)
+	slices.SortFunc(folderNameList, compareFolders)
 
 	if len(folderNameList) == 0 {
 		return
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	templatesDirName    = "templates"
+	defaultTemplateName = "default"
+
+	newUsage    = "Usage: mdf new <folder>/<name> [--template <name>]"
+	removeUsage = "Usage: mdf rm <folder>/<file>"
+	moveUsage   = "Usage: mdf mv <folder>/<file> <folder>/<name>"
+)
+
+// parseSnippetTarget parses <folder>/<name> into the folder and file name,
+// the folder may be nested and the file name defaults to the markdown
+// extension.
+func parseSnippetTarget(target string) (folder, file string, err error) {
+	target = strings.Trim(filepath.ToSlash(strings.TrimSpace(target)), "/")
+	folder, file = path.Split(target)
+	folder = strings.TrimSuffix(folder, "/")
+	if folder == "" || file == "" || strings.HasPrefix(file, ".") {
+		return "", "", fmt.Errorf("invalid snippet %q, use <folder>/<name>", target)
+	}
+	// hidden folders are skipped by the scan, and .. would leave the repo
+	for _, part := range strings.Split(folder, "/") {
+		if part == "" || strings.HasPrefix(part, ".") {
+			return "", "", fmt.Errorf("invalid folder %q", folder)
+		}
+	}
+	if filepath.Ext(file) == "" {
+		file += "." + defaultLanguage
+	}
+	return folder, file, nil
+}
+
+// newSnippet returns the snippet of the folder and file name.
+func newSnippet(folder, file string) Snippet {
+	ext := filepath.Ext(file)
+	return Snippet{
+		Folder:   folder,
+		Date:     time.Now(),
+		Name:     strings.TrimSuffix(file, ext),
+		File:     file,
+		Language: strings.TrimPrefix(ext, "."),
+	}
+}
+
+// readTemplate returns the content of the template in the templates
+// directory. Without a name the default template is used if it exists.
+func readTemplate(config Config, name string, snippet Snippet) (string, error) {
+	dir := filepath.Join(config.Home, templatesDirName)
+	explicit := name != ""
+	if !explicit {
+		name = defaultTemplateName
+	}
+
+	for _, file := range []string{name, name + "." + defaultLanguage} {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err == nil {
+			return string(content), nil
+		}
+	}
+	if explicit {
+		return "", fmt.Errorf("template %q not found in %s", name, dir)
+	}
+	return fmt.Sprintf("## %s\n", snippet.Name), nil
+}
+
+// createSnippetFile creates the file of the <folder>/<name> snippet.
+func createSnippetFile(config Config, target, template string) (Snippet, error) {
+	folder, file, err := parseSnippetTarget(target)
+	if err != nil {
+		return Snippet{}, err
+	}
+	snippet := newSnippet(folder, file)
+	if config.isAllRepos() {
+		snippet.Repo = config.getRepoName()
+	}
+
+	filePath := config.getSnippetPath(snippet)
+	if _, err = os.Stat(filePath); err == nil {
+		return Snippet{}, fmt.Errorf("snippet %s already exists", snippet.Path())
+	}
+
+	content, err := readTemplate(config, template, snippet)
+	if err != nil {
+		return Snippet{}, err
+	}
+	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return Snippet{}, fmt.Errorf("failed to create folder: %w", err)
+	}
+	if err = os.WriteFile(filePath, []byte(content), os.ModePerm); err != nil {
+		return Snippet{}, fmt.Errorf("failed to create snippet: %w", err)
+	}
+	return snippet.withFrontMatter(parseFrontMatter(content)), nil
+}
+
+// moveSnippetFile renames the snippet file to <folder>/<name>, the snippet
+// keeps its date and usage.
+func moveSnippetFile(config Config, snippet Snippet, target string) (Snippet, error) {
+	folder, file, err := parseSnippetTarget(target)
+	if err != nil {
+		return Snippet{}, err
+	}
+	moved := newSnippet(folder, file)
+	moved.Date = snippet.Date
+	moved.Repo = snippet.Repo
+	moved.Usage, moved.SectionUsage = snippet.Usage, snippet.SectionUsage
+
+	newPath := config.getSnippetPath(moved)
+	if _, err = os.Stat(newPath); err == nil {
+		return Snippet{}, fmt.Errorf("snippet %s already exists", moved.Path())
+	}
+	if err = os.MkdirAll(filepath.Dir(newPath), os.ModePerm); err != nil {
+		return Snippet{}, fmt.Errorf("failed to create folder: %w", err)
+	}
+	if err = os.Rename(config.getSnippetPath(snippet), newPath); err != nil {
+		return Snippet{}, fmt.Errorf("failed to move snippet: %w", err)
+	}
+	return readFrontMatter(config, moved), nil
+}
+
+// deleteSnippetFile deletes the snippet file.
+func deleteSnippetFile(config Config, snippet Snippet) error {
+	if err := os.Remove(config.getSnippetPath(snippet)); err != nil {
+		return fmt.Errorf("failed to delete snippet: %w", err)
+	}
+	return nil
+}
+
+// findSnippetByPath returns the snippet of the exact <folder>/<file> or
+// <folder>/<name> path. Unlike findSnippet it never guesses, so it is used
+// for destructive commands.
+func findSnippetByPath(target string, snippets []Snippet) (int, bool) {
+	target = strings.Trim(filepath.ToSlash(target), "/")
+	for i, snippet := range snippets {
+		p := filepath.ToSlash(snippet.Path())
+		if target == p || target == strings.TrimSuffix(p, filepath.Ext(p)) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// runNew creates a snippet from the command line.
+func runNew(config Config, snippets []Snippet, args []string) error {
+	parsed := parseArgs(args, "template")
+	if parsed.arg(0) == "" {
+		return errors.New(newUsage)
+	}
+
+	snippet, err := createSnippetFile(config, parsed.arg(0), parsed.get("template"))
+	if err != nil {
+		return err
+	}
+	writeSnippets(config, append(snippets, snippet))
+	fmt.Printf("Created snippet: %s\n", config.getSnippetPath(snippet))
+	return nil
+}
+
+// runRemove deletes a snippet from the command line.
+func runRemove(config Config, snippets []Snippet, args []string) error {
+	parsed := parseArgs(args)
+	if parsed.arg(0) == "" {
+		return errors.New(removeUsage)
+	}
+
+	i, ok := findSnippetByPath(parsed.arg(0), snippets)
+	if !ok {
+		return fmt.Errorf("snippet %s not found", parsed.arg(0))
+	}
+	if err := deleteSnippetFile(config, snippets[i]); err != nil {
+		return err
+	}
+	removed := snippets[i]
+	writeSnippets(config, slicesDelete(snippets, i))
+	moveSnippetUsage(config, removed, Snippet{})
+	fmt.Printf("Removed snippet: %s\n", removed.Path())
+	return nil
+}
+
+// runMove renames a snippet from the command line.
+func runMove(config Config, snippets []Snippet, args []string) error {
+	parsed := parseArgs(args)
+	if parsed.arg(0) == "" || parsed.arg(1) == "" {
+		return errors.New(moveUsage)
+	}
+
+	i, ok := findSnippetByPath(parsed.arg(0), snippets)
+	if !ok {
+		return fmt.Errorf("snippet %s not found", parsed.arg(0))
+	}
+	moved, err := moveSnippetFile(config, snippets[i], parsed.arg(1))
+	if err != nil {
+		return err
+	}
+	old := snippets[i]
+	snippets[i] = moved
+	writeSnippets(config, snippets)
+	moveSnippetUsage(config, old, moved)
+	fmt.Printf("Moved snippet: %s -> %s\n", old.Path(), moved.Path())
+	return nil
+}
+
+// slicesDelete removes the snippet at index i.
+func slicesDelete(snippets []Snippet, i int) []Snippet {
+	return append(snippets[:i:i], snippets[i+1:]...)
+}
+
+// snippetCreatedMsg is sent when the new snippet prompt is submitted.
+type snippetCreatedMsg string
+
+// snippetMovedMsg is sent when the rename snippet prompt is submitted.
+type snippetMovedMsg string
+
+// snippetDeletedMsg is sent when the delete snippet is confirmed.
+type snippetDeletedMsg struct{}
+
+// promptNewSnippet opens the prompt for the <folder>/<name> of a new snippet.
+func (m *Model) promptNewSnippet() tea.Cmd {
+	value := string(m.selectedFolder()) + "/"
+	m.overlay = newPromptOverlay("New snippet <folder>/<name>", value, func(value string) tea.Msg {
+		return snippetCreatedMsg(value)
+	})
+	return nil
+}
+
+// promptMoveSnippet opens the prompt for the new <folder>/<name> of the
+// selected snippet.
+func (m *Model) promptMoveSnippet() tea.Cmd {
+	if len(m.Snippets().Items()) == 0 {
+		return nil
+	}
+	snippet := m.selectedSnippet()
+	value := filepath.ToSlash(snippet.Path())
+	m.overlay = newPromptOverlay("Rename "+value+" to <folder>/<name>", value, func(value string) tea.Msg {
+		return snippetMovedMsg(value)
+	})
+	return nil
+}
+
+// confirmDeleteSnippet asks for confirmation before the selected snippet is
+// deleted.
+func (m *Model) confirmDeleteSnippet() tea.Cmd {
+	if len(m.Snippets().Items()) == 0 {
+		return nil
+	}
+	m.overlay = &confirmOverlay{
+		title:     "Delete " + m.selectedSnippet().Path() + "?",
+		body:      m.config.getSnippetPath(m.selectedSnippet()),
+		onConfirm: snippetDeletedMsg{},
+	}
+	return nil
+}
+
+// updateSnippetFile handles the created, moved and deleted snippet messages.
+func (m *Model) updateSnippetFile(msg tea.Msg) tea.Cmd {
+	m.overlay = nil
+
+	switch msg := msg.(type) {
+	case snippetCreatedMsg:
+		snippet, err := createSnippetFile(m.config, string(msg), "")
+		if err != nil {
+			return m.showError(err)
+		}
+		m.insertSnippet(snippet)
+		writeSnippets(m.config, m.allSnippets())
+		return m.editSnippet()
+	case snippetMovedMsg:
+		old := m.selectedSnippet()
+		snippet, err := moveSnippetFile(m.config, old, string(msg))
+		if err != nil {
+			return m.showError(err)
+		}
+		m.removeSnippet(old)
+		m.insertSnippet(snippet)
+		writeSnippets(m.config, m.allSnippets())
+		moveSnippetUsage(m.config, old, snippet)
+	case snippetDeletedMsg:
+		snippet := m.selectedSnippet()
+		if err := deleteSnippetFile(m.config, snippet); err != nil {
+			return m.showError(err)
+		}
+		m.removeSnippet(snippet)
+		writeSnippets(m.config, m.allSnippets())
+		moveSnippetUsage(m.config, snippet, Snippet{})
+	}
+	return m.updateContent()
+}
+
+// insertSnippet adds the snippet to the list of its folder and selects it.
+func (m *Model) insertSnippet(snippet Snippet) {
+	folder := Folder(snippet.Folder)
+	snippetList, ok := m.SnippetsMap[folder]
+	if !ok {
+		snippetList = newList(nil, m.height, m.SnippetStyle)
+		m.SnippetsMap[folder] = snippetList
+
+		items := m.Folders.Items()
+		idx := len(items)
+		for i, item := range items {
+			if compareFolders(string(item.(Folder)), snippet.Folder) > 0 {
+				idx = i
+				break
+			}
+		}
+		m.Folders.InsertItem(idx, folder)
+	}
+
+	for i, item := range m.Folders.Items() {
+		if item.(Folder) == folder {
+			m.Folders.Select(i)
+			m.config.FolderName = snippet.Folder
+			break
+		}
+	}
+
+	snippetList.InsertItem(0, snippet)
+	snippetList.Select(0)
+}
+
+// removeSnippet removes the snippet from the list of its folder. The folder
+// is removed with its last snippet, unless it is the only folder.
+func (m *Model) removeSnippet(snippet Snippet) {
+	delete(m.SectionsMap, snippet.key())
+	folder := Folder(snippet.Folder)
+	snippetList, ok := m.SnippetsMap[folder]
+	if !ok {
+		return
+	}
+	for i, item := range snippetList.Items() {
+		if item.(Snippet).key() == snippet.key() {
+			snippetList.RemoveItem(i)
+			break
+		}
+	}
+
+	folders := m.Folders.Items()
+	if len(snippetList.Items()) > 0 || len(folders) < 2 {
+		return
+	}
+	delete(m.SnippetsMap, folder)
+	for i, item := range folders {
+		if item.(Folder) == folder {
+			m.Folders.RemoveItem(i)
+			m.Folders.Select(min(i, len(folders)-2))
+			break
+		}
+	}
+	m.config.FolderName = string(m.selectedFolder())
+}
+
+// allSnippets returns the snippets of every folder, including the snippets
+// hidden by the tag filter.
+func (m *Model) allSnippets() []Snippet {
+	snippets := append([]Snippet(nil), m.hiddenSnippets...)
+	for _, snippetList := range m.SnippetsMap {
+		for _, item := range snippetList.Items() {
+			snippets = append(snippets, item.(Snippet))
+		}
+	}
+	return snippets
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestRepo returns the config of a repo in a temporary home with the
+// snippet files.
+func newTestRepo(t *testing.T, files map[string]string) Config {
+	t.Helper()
+	config := newConfig()
+	config.Home = t.TempDir()
+	config.RepoName = "local/repo"
+	for name, content := range files {
+		file := filepath.Join(config.getRepoPath(), filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return config
+}
+
+func TestParseSnippetTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		folder string
+		file   string
+		ok     bool
+	}{
+		{"k8s/pods", "k8s", "pods.md", true},
+		{"k8s/pods.sh", "k8s", "pods.sh", true},
+		{"/cloud/k8s/pods/", "cloud/k8s", "pods.md", true},
+		{"pods", "", "", false},
+		{"k8s/", "", "", false},
+		{"k8s/.pods", "", "", false},
+		{".git/pods", "", "", false},
+		{"k8s/../pods", "", "", false},
+	}
+	for _, tt := range tests {
+		folder, file, err := parseSnippetTarget(tt.target)
+		if (err == nil) != tt.ok || folder != tt.folder || file != tt.file {
+			t.Errorf("parseSnippetTarget(%q) = %q, %q, %v, want %q, %q, ok %v", tt.target, folder, file, err, tt.folder, tt.file, tt.ok)
+		}
+	}
+}
+
+func TestRunNew(t *testing.T) {
+	config := newTestRepo(t, map[string]string{"k8s/pods.md": "# Pods\n"})
+	if err := runNew(config, nil, []string{"k8s/logs"}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(config.getRepoPath(), "k8s", "logs.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "## logs\n" {
+		t.Errorf("new snippet content = %q, want the default template", content)
+	}
+	if _, ok := findSnippetByPath("k8s/logs", readSnippets(config)); !ok {
+		t.Errorf("new snippet is not in the snippets file")
+	}
+
+	if err = runNew(config, nil, []string{"k8s/pods"}); err == nil {
+		t.Errorf("runNew of an existing snippet returned no error")
+	}
+	if err = runNew(config, nil, []string{"k8s/logs", "--template", "missing"}); err == nil {
+		t.Errorf("runNew with a missing template returned no error")
+	}
+}
+
+func TestRunRemove(t *testing.T) {
+	config := newTestRepo(t, map[string]string{"k8s/pods.md": "# Pods\n", "k8s/logs.md": "# Logs\n"})
+	snippets := loadSnippets(config)
+	if err := runRemove(config, snippets, []string{"k8s/po"}); err == nil {
+		t.Errorf("runRemove of a partial path returned no error")
+	}
+	if err := runRemove(config, snippets, []string{"k8s/pods"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(config.getRepoPath(), "k8s", "pods.md")); !os.IsNotExist(err) {
+		t.Errorf("removed snippet file still exists: %v", err)
+	}
+	snippets = readSnippets(config)
+	if _, ok := findSnippetByPath("k8s/pods.md", snippets); ok {
+		t.Errorf("removed snippet is still in the snippets file")
+	}
+	if _, ok := findSnippetByPath("k8s/logs.md", snippets); !ok {
+		t.Errorf("other snippet is dropped from the snippets file")
+	}
+}
+
+func TestRunMove(t *testing.T) {
+	config := newTestRepo(t, map[string]string{"k8s/pods.md": "---\ntitle: Pods\n---\n# List\n", "k8s/logs.md": "# Logs\n"})
+	snippets := loadSnippets(config)
+	i, _ := findSnippetByPath("k8s/pods", snippets)
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	snippets[i].Date = date
+	snippets[i] = snippets[i].withUsage("list", true, date)
+
+	if err := runMove(config, snippets, []string{"k8s/pods", "k8s/logs"}); err == nil {
+		t.Errorf("runMove onto an existing snippet returned no error")
+	}
+	if err := runMove(config, snippets, []string{"k8s/pods", "cloud/k8s/workloads"}); err != nil {
+		t.Fatal(err)
+	}
+
+	moved := loadSnippets(config)
+	j, ok := findSnippetByPath("cloud/k8s/workloads.md", moved)
+	if !ok {
+		t.Fatalf("moved snippet is not in the snippets file")
+	}
+	if _, ok = findSnippetByPath("k8s/pods.md", moved); ok {
+		t.Errorf("old snippet is still in the snippets file")
+	}
+	if got := moved[j]; got.Name != "Pods" || !got.Date.Equal(date) {
+		t.Errorf("moved snippet name and date = %q, %v, want Pods, %v", got.Name, got.Date, date)
+	}
+	if got := moved[j].SectionUsage["list"]; got == nil || got.Copies != 1 {
+		t.Errorf("moved snippet section usage = %+v, want 1 copy", got)
+	}
+	usages, err := readUsage(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = usages["local/repo/k8s/pods.md"]; ok {
+		t.Errorf("usage of the old path is kept")
+	}
+}
+
+func TestRemoveSnippetFolder(t *testing.T) {
+	config := newTestRepo(t, nil)
+	config.FolderName = "k8s"
+	pods := Snippet{Folder: "k8s", File: "pods.md"}
+	logs := Snippet{Folder: "k8s", File: "logs.md"}
+	deploy := Snippet{Folder: "team", File: "deploy.md"}
+	m := newModel(config, []Snippet{pods, logs, deploy}, Snippet{})
+
+	m.removeSnippet(pods)
+	if got := len(m.Folders.Items()); got != 2 {
+		t.Fatalf("folders after removing a snippet = %d, want 2", got)
+	}
+	m.removeSnippet(logs)
+	if got := len(m.Folders.Items()); got != 1 {
+		t.Fatalf("folders after removing the last snippet = %d, want 1", got)
+	}
+	if _, ok := m.SnippetsMap["k8s"]; ok {
+		t.Errorf("the snippet list of the removed folder is kept")
+	}
+	if got := m.selectedFolder(); got != "team" || m.config.FolderName != "team" {
+		t.Errorf("selected folder = %q, config folder = %q, want team", got, m.config.FolderName)
+	}
+
+	// the only folder is kept
+	m.removeSnippet(deploy)
+	if got := len(m.Folders.Items()); got != 1 {
+		t.Errorf("folders after removing every snippet = %d, want 1", got)
+	}
+}
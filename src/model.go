@@ -4,11 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/atotto/clipboard"
+	"github.com/aquilax/truncate"
 	"github.com/charmbracelet/bubbles/help"
 	bkey "github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -16,17 +15,18 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/yuin/goldmark"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/text"
 )
 
-const maxPane = 3
+const maxPane = 4
 
 type pane int
 
 const (
-	snippetPane pane = iota
+	folderPane pane = iota
+	snippetPane
 	sectionPane
 	contentPane
 )
@@ -38,6 +38,7 @@ const (
 	copyingState
 	quittingState
 	editingState
+	errorState
 )
 
 // Model represents the state of the application.
@@ -51,10 +52,12 @@ type Model struct {
 	help help.Model
 	// the height of the terminal.
 	height int
+	// the width of the terminal.
+	width int
 	// the working directory.
 	Workdir string
-	// the map of Sections to display to the user.
-	SectionsMap map[Snippet]*list.Model
+	// the map of Sections to display to the user, by the key of the snippet.
+	SectionsMap map[string]*list.Model
 	// the map of Snippets to display to the user.
 	SnippetsMap map[Folder]*list.Model
 	// the list of Folders to display to the user.
@@ -67,6 +70,7 @@ type Model struct {
 	// the current state / action of the application.
 	state state
 	// stying for components
+	FolderStyle  FoldersBaseStyle
 	SnippetStyle SnippetsBaseStyle
 	SectionStyle SectionsBaseStyle
 	ContentStyle ContentBaseStyle
@@ -74,21 +78,58 @@ type Model struct {
 	mdRender *glamour.TermRenderer
 	// default is true
 	hideSnippetPane bool
+	// the modal overlay drawn instead of the panes, nil if none is open.
+	overlay overlay
+	// the last code block run, its output replaces the content view.
+	run *blockRun
+	// the error message shown in the title bar of the errorState.
+	errMsg string
+	// the tag the snippets are filtered by, and the snippets without it.
+	tag            string
+	hiddenSnippets []Snippet
+	// whether the lists are sorted by frecency, and the snippet order before.
+	sortFrecent  bool
+	snippetOrder map[string]int
+	// the last section recorded as opened, by snippet key.
+	openedSections map[string]string
+	// the last copy of the stdout clipboard backend, printed on exit.
+	output string
+	// the cursor on the code blocks of the content pane.
+	blocks blockCursor
+	// the hint labels of the code blocks.
+	hints hintMode
+	// the find in the content pane.
+	find contentFind
+	// the slug of the section selected on start.
+	targetSection string
 }
 
 // Init initialzes the application model.
 func (m *Model) Init() tea.Cmd {
-	m.SectionsMap = make(map[Snippet]*list.Model)
+	m.SectionsMap = make(map[string]*list.Model)
 	m.pane = m.defaultPane()
+	if m.hiddenPane(m.pane) {
+		m.pane = sectionPane
+	}
 	m.keys = m.config.newKeyMap()
 	m.updateStyleByPane()
 	m.updateKeyMap()
+	if m.targetSection != "" {
+		m.selectSection(m.targetSection)
+	}
 
 	return func() tea.Msg {
 		return updateContentMsg(m.selectedSection())
 	}
 }
 
+// showError returns a Cmd to show the error in the title bar of the active
+// pane.
+func (m *Model) showError(err error) tea.Cmd {
+	m.errMsg = err.Error()
+	return changeState(errorState)
+}
+
 // updateContentMsg tells the application to update the content view with the
 // given section.
 type updateContentMsg Section
@@ -115,8 +156,54 @@ func changeState(newState state) tea.Cmd {
 
 // Update updates the model based on user interaction.
 func (m *Model) Update(teaMsg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := teaMsg.(tea.KeyMsg); ok && m.overlay != nil {
+		var cmd tea.Cmd
+		m.overlay, cmd = m.overlay.Update(teaMsg)
+		return m, cmd
+	}
+
 	switch msg := teaMsg.(type) {
+	case overlayClosedMsg:
+		m.overlay = nil
+		return m, nil
+	case placeholdersFilledMsg:
+		m.overlay = nil
+		_ = writeVars(m.config, msg.values)
+		if msg.action == runAction {
+			return m, m.startRun(msg.content)
+		}
+		return m, m.writeClipboard(msg.content, msg.block, msg.action == copyExitAction)
+	case runConfirmedMsg:
+		m.overlay = nil
+		codeBlock := CodeBlock(msg)
+		if placeholders := parsePlaceholders(codeBlock); len(placeholders) > 0 {
+			m.overlay = newPlaceholderForm(codeBlock, placeholders, readVars(m.config), runAction)
+			return m, nil
+		}
+		return m, m.startRun(codeBlock.Content)
+	case tagSelectedMsg:
+		return m, m.filterTag(string(msg))
+	case searchChosenMsg:
+		return m, m.jumpTo(searchHit(msg))
+	case sectionOpenedMsg:
+		m.recordSectionOpen(msg)
+		return m, nil
+	case searchClosedMsg:
+		m.overlay = nil
+		return m, nil
+	case repoSelectedMsg:
+		return m, m.switchRepo(string(msg))
+	case historySelectedMsg:
+		m.overlay = nil
+		return m, m.recopy(historyEntry(msg))
+	case snippetCreatedMsg, snippetMovedMsg, snippetDeletedMsg:
+		return m, m.updateSnippetFile(msg)
+	case runOutputMsg, runTickMsg, runDoneMsg:
+		return m, m.updateRunOutput(msg)
 	case updateContentMsg:
+		if m.run != nil {
+			m.run.visible = false
+		}
 		return m.updateContentView(msg)
 	case changeStateMsg:
 		m.Snippets().SetDelegate(snippetDelegate{m.pane, m.SnippetStyle, msg.newState})
@@ -139,6 +226,10 @@ func (m *Model) Update(teaMsg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = tea.Tick(time.Second, func(t time.Time) tea.Msg {
 				return changeStateMsg{navigatingState}
 			})
+		case errorState:
+			cmd = tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+				return changeStateMsg{navigatingState}
+			})
 		default:
 			// do nothing
 		}
@@ -151,21 +242,33 @@ func (m *Model) Update(teaMsg tea.Msg) (tea.Model, tea.Cmd) {
 		for _, li := range m.SnippetsMap {
 			li.SetHeight(m.height)
 		}
+		m.Folders.SetHeight(m.height)
 		m.Code.Height = m.height
 		m.LineNumbers.Height = m.height
-		m.Code.Width = msg.Width - m.Snippets().Width() - m.Folders.Width() - 20
+		m.width = msg.Width
+		m.resizeContent()
 		m.LineNumbers.Width = 5
 		return m, nil
 	case tea.KeyMsg:
-		if m.Snippets().FilterState() == list.Filtering {
-			break
-		}
-		if m.Sections().FilterState() == list.Filtering {
-			break
+		if m.state == copyingState || m.state == errorState {
+			return m, changeState(navigatingState)
 		}
 
-		if m.state == copyingState {
-			return m, changeState(navigatingState)
+		if m.hints.active {
+			return m, m.updateHints(msg)
+		}
+		if m.find.typing {
+			return m, m.updateFindInput(msg)
+		}
+		if m.pane == contentPane && m.find.query != "" {
+			if cmd, ok := m.updateFind(msg); ok {
+				return m, cmd
+			}
+		}
+		if m.blocks.active {
+			if cmd, ok := m.updateBlockCursor(msg); ok {
+				return m, cmd
+			}
 		}
 
 		switch {
@@ -193,36 +296,47 @@ func (m *Model) Update(teaMsg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Folders.SetHeight(newHeight)
 			m.Code.Height = newHeight
 			m.LineNumbers.Height = newHeight
-		case bkey.Matches(msg, m.keys.CopyContent):
-			if m.config.ExitAfterCopy {
-				content, ok := m.getContentToCopy(msg)
-				if ok {
-					_ = clipboard.WriteAll(content)
-				}
-				m.state = quittingState
-				return m, tea.Quit
-			}
-			return m, func() tea.Msg {
-				content, ok := m.getContentToCopy(msg)
-				if !ok {
-					return changeStateMsg{navigatingState}
-				}
-				_ = clipboard.WriteAll(content)
-				return changeStateMsg{copyingState}
-			}
-		case bkey.Matches(msg, m.keys.CopyContentExit):
-			content, ok := m.getContentToCopy(msg)
-			if ok {
-				_ = clipboard.WriteAll(content)
-			}
-			m.state = quittingState
-			return m, tea.Quit
+		case m.pane != folderPane && bkey.Matches(msg, m.keys.CopyContent):
+			return m, m.copyContent(msg, m.config.ExitAfterCopy)
+		case m.pane != folderPane && bkey.Matches(msg, m.keys.CopyContentExit):
+			return m, m.copyContent(msg, true)
 		case bkey.Matches(msg, m.keys.EditSnippet):
 			return m, m.editSnippet()
+		case m.pane == snippetPane && bkey.Matches(msg, m.keys.NewSnippet):
+			return m, m.promptNewSnippet()
+		case m.pane == snippetPane && bkey.Matches(msg, m.keys.RenameSnippet):
+			return m, m.promptMoveSnippet()
+		case m.pane == snippetPane && bkey.Matches(msg, m.keys.DeleteSnippet):
+			return m, m.confirmDeleteSnippet()
+		case bkey.Matches(msg, m.keys.PickTag):
+			return m, m.pickTag()
+		case bkey.Matches(msg, m.keys.SortFrecent):
+			return m, m.toggleFrecentSort()
+		case bkey.Matches(msg, m.keys.SwitchRepo):
+			return m, m.pickRepo()
+		case bkey.Matches(msg, m.keys.CopyHistory):
+			return m, m.showHistory()
+		case bkey.Matches(msg, m.keys.CopyLink):
+			return m, m.copyLink()
+		case bkey.Matches(msg, m.keys.RunBlock):
+			return m, m.confirmRun()
+		case m.pane == contentPane && bkey.Matches(msg, m.keys.Find):
+			return m, m.startFind()
+		case m.pane == contentPane && bkey.Matches(msg, m.keys.BlockCursor):
+			return m, m.toggleBlockCursor()
+		case m.pane != snippetPane && bkey.Matches(msg, m.keys.Hint):
+			return m, m.startHints()
+		case msg.Type == tea.KeyEsc && m.pane == contentPane && m.run != nil && m.run.visible:
+			return m, m.updateContent()
 		case bkey.Matches(msg, m.keys.Search):
-			//m.pane = sectionPane
+			return m, m.openSearch()
 		case bkey.Matches(msg, m.keys.ToggleSnippetPane):
 			m.hideSnippetPane = !m.hideSnippetPane
+			m.resizeContent()
+			if m.hiddenPane(m.pane) {
+				m.pane = sectionPane
+				m.updateStyleByPane()
+			}
 			return m, nil
 		}
 	}
@@ -232,16 +346,78 @@ func (m *Model) Update(teaMsg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// getContentToCopy
-func (m *Model) getContentToCopy(msg tea.KeyMsg) (string, bool) {
+// copyContent copies the content for the pressed copy key.
+func (m *Model) copyContent(msg tea.KeyMsg, exit bool) tea.Cmd {
+	codeBlock, ok := m.getContentToCopy(msg)
+	if !ok {
+		if exit {
+			m.state = quittingState
+			return tea.Quit
+		}
+		return changeState(navigatingState)
+	}
+	return m.copyCodeBlock(codeBlock, exit)
+}
+
+// copyCodeBlock copies the code block. Code blocks with placeholders open the
+// placeholder form first.
+func (m *Model) copyCodeBlock(codeBlock CodeBlock, exit bool) tea.Cmd {
+	if placeholders := parsePlaceholders(codeBlock); len(placeholders) > 0 {
+		action := copyAction
+		if exit {
+			action = copyExitAction
+		}
+		m.overlay = newPlaceholderForm(codeBlock, placeholders, readVars(m.config), action)
+		return nil
+	}
+	return m.writeClipboard(codeBlock.Content, codeBlock.Index, exit)
+}
+
+// writeClipboard writes the content to the clipboard, and records the copy
+// of the selected snippet or section in the usage and the history.
+func (m *Model) writeClipboard(content string, block int, exit bool) tea.Cmd {
+	if err := m.setClipboard(content); err != nil {
+		return m.showError(err)
+	}
+	m.recordUsage(true)
+	m.recordHistory(content, block)
+	return m.copied(exit)
+}
+
+// setClipboard writes the content with the clipboard backend. The stdout
+// backend prints the content when the application exits.
+func (m *Model) setClipboard(content string) error {
+	if m.config.ClipboardBackend == clipboardStdout {
+		m.output = content
+		return nil
+	}
+	if err := copyToClipboard(m.config, content); err != nil {
+		return fmt.Errorf("copy failed: %w", err)
+	}
+	return nil
+}
+
+// copied quits or shows the copied state.
+func (m *Model) copied(exit bool) tea.Cmd {
+	if exit {
+		m.state = quittingState
+		return tea.Quit
+	}
+	return changeState(copyingState)
+}
+
+// getContentToCopy returns the snippet file in the snippet pane, or the
+// copyable code block of the pressed copy key in the section and content
+// panes. The copy keys aren't handled in the folder pane.
+func (m *Model) getContentToCopy(msg tea.KeyMsg) (CodeBlock, bool) {
 	switch m.pane {
 	case snippetPane:
 		// copy snippet
 		contentBytes, err := os.ReadFile(m.selectedSnippetFilePath())
 		if err != nil {
-			return "", false
+			return CodeBlock{}, false
 		}
-		return string(contentBytes), true
+		return CodeBlock{Content: string(contentBytes)}, true
 	default:
 		// copy section code block
 		key := strings.ToLower(msg.String())
@@ -260,45 +436,62 @@ func (m *Model) getContentToCopy(msg tea.KeyMsg) (string, bool) {
 			if copyable {
 				copyCount++
 				if keyIndex+1 == copyCount {
-					return codeBlock.Content, true
+					return codeBlock, true
 				}
 			}
 		}
 	}
-	return "", false
+	return CodeBlock{}, false
 }
 
 // selectedSnippetFilePath returns the file path of the snippet that is
 // currently selected.
 func (m *Model) selectedSnippetFilePath() string {
-	return filepath.Join(m.config.getRepoPath(), m.selectedSnippet().Path())
+	return m.config.getSnippetPath(m.selectedSnippet())
 }
 
 // nextPane sets the next pane to be active.
 func (m *Model) nextPane() {
 	m.pane = (m.pane + 1) % maxPane
-	if m.hideSnippetPane && m.pane == snippetPane {
-		m.pane = sectionPane
+	for m.hiddenPane(m.pane) {
+		m.pane = (m.pane + 1) % maxPane
 	}
 }
 
 // previousPane sets the previous pane to be active.
 func (m *Model) previousPane() {
-	m.pane--
-	if m.pane < 0 {
-		m.pane = maxPane - 1
+	m.pane = (m.pane + maxPane - 1) % maxPane
+	for m.hiddenPane(m.pane) {
+		m.pane = (m.pane + maxPane - 1) % maxPane
 	}
-	if m.hideSnippetPane && m.pane == snippetPane {
-		m.pane = contentPane
+}
+
+// hiddenPane reports whether the pane is hidden, the folder and the snippet
+// panes are hidden in two-pane mode.
+func (m *Model) hiddenPane(p pane) bool {
+	return m.hideSnippetPane && (p == folderPane || p == snippetPane)
+}
+
+// resizeContent sets the width of the content pane, the folder pane only
+// takes space when it is shown.
+func (m *Model) resizeContent() {
+	width := m.width - m.Snippets().Width() - 20
+	if !m.hiddenPane(folderPane) {
+		width -= m.Folders.Width()
 	}
+	m.Code.Width = width
 }
 
 // editSnippet opens the editor with the selected snippet file path.
 func (m *Model) editSnippet() tea.Cmd {
 	// 保存当前选中的 section 下标
 	currentIndex := m.Sections().Index()
+	m.recordUsage(false)
 
 	return tea.ExecProcess(editorCmd(m.selectedSnippetFilePath()), func(err error) tea.Msg {
+		// the front matter may have changed
+		snippets := m.Snippets()
+		snippets.SetItem(snippets.Index(), readFrontMatter(m.config, m.selectedSnippet()))
 		m.updateSnippetSections(m.selectedSnippet())
 
 		// 恢复之前选中的 section 位置
@@ -320,51 +513,27 @@ func (m *Model) updateSnippetSections(snippet Snippet) {
 	sections := list.New(itemList, delegate, 25, 20)
 	sections.SetShowHelp(false)
 	sections.SetShowFilter(false)
+	// "/" opens the search overlay instead of the list filter
+	sections.SetFilteringEnabled(false)
 	sections.SetShowTitle(false)
 	sections.Styles.StatusBar = lipgloss.NewStyle().Margin(1, 2).Foreground(lipgloss.Color("240")).MaxWidth(35 - 2)
 	sections.Styles.NoItems = lipgloss.NewStyle().Margin(0, 2).Foreground(lipgloss.Color("8")).MaxWidth(35 - 2)
-	sections.FilterInput.Prompt = "Find: "
-	sections.FilterInput.PromptStyle = styles.Title
 	sections.SetStatusBarItemName("Section", "Sections")
 	sections.DisableQuitKeybindings()
 	sections.Styles.Title = styles.Title
 	sections.Styles.TitleBar = styles.TitleBar
 
-	m.SectionsMap[snippet] = &sections
+	m.SectionsMap[snippet.key()] = &sections
 
 	if len(m.Snippets().Items()) <= 0 {
 		return
 	}
 
-	repoPath := m.config.getRepoPath()
-	snippetContentBytes, err := os.ReadFile(filepath.Join(repoPath, snippet.Path()))
+	sectionSlice, err := readSections(m.config, snippet)
 	if err != nil {
 		return
 	}
-	snippetContent := strings.TrimSpace(string(snippetContentBytes))
-
-	if snippetContent == "" {
-		return
-	}
-
-	// split snippetContent to sections
-	contentParts := strings.Split(snippetContent, "\n---\n")
-	sectionSlice := make([]Section, 0, len(contentParts))
-	for _, content := range contentParts {
-		mdElem := &MarkdownElem{}
-		content = strings.TrimSpace(content)
-		mdElem, err = m.parseMarkdown(content)
-		if err != nil {
-			continue
-		}
-		sectionSlice = append(sectionSlice, Section{
-			Folder:     snippet.Folder,
-			File:       snippet.File,
-			Content:    content,
-			Title:      mdElem.FirstTitle,
-			CodeBlocks: mdElem.CodeBlocks,
-		})
-	}
+	m.sortSections(snippet, sectionSlice)
 
 	for i, sec := range sectionSlice {
 		sections.InsertItem(i, list.Item(sec))
@@ -382,18 +551,52 @@ func (m *Model) updateContentView(msg updateContentMsg) (tea.Model, tea.Cmd) {
 	c, _ := m.mdRender.Render(section.Content)
 	c = strings.TrimPrefix(c, "\n")
 	c = strings.ReplaceAll(c, "\t", strings.Repeat(" ", tabSpaces))
+	blocks := findBlockLines(c, m.config, section)
 	c = m.handleCodeBlockBorder(c, section)
-	m.writeLineNumbers(lipgloss.Height(c))
-	m.Code.SetContent(c)
+	m.blocks = blockCursor{content: c, lines: blocks}
+	m.find.matches = findMatches(c, m.find.query)
+	m.find.index = min(m.find.index, max(0, len(m.find.matches)-1))
+	m.renderContent()
 
 	return m, nil
 }
 
+// renderContent writes the content of the section to the content view with
+// the matches of the find and the block cursor highlighted.
+func (m *Model) renderContent() {
+	content := m.blocks.content
+	if m.find.query != "" || m.blocks.active {
+		lines := strings.Split(content, "\n")
+		m.find.highlightMatches(lines)
+		if m.blocks.active {
+			style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.config.SelectedItemFgColor))
+			cursor := m.blocks.lines[m.blocks.index]
+			for _, i := range []int{cursor.start, cursor.end} {
+				lines[i] = style.Render(ansi.Strip(lines[i]))
+			}
+		}
+		content = strings.Join(lines, "\n")
+	}
+	m.writeLineNumbers(lipgloss.Height(content))
+	m.Code.SetContent(content)
+}
+
 // writeLineNumbers writes the number of line numbers to the line number
-// viewport.
+// viewport, the lines of the code block under the block cursor are
+// highlighted.
 func (m *Model) writeLineNumbers(n int) {
+	var cursor blockLines
+	if m.blocks.active {
+		cursor = m.blocks.lines[m.blocks.index]
+	}
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.config.SelectedItemFgColor))
+
 	var lineNumbers strings.Builder
 	for i := 1; i < n; i++ {
+		if m.blocks.active && i-1 >= cursor.start && i-1 <= cursor.end {
+			lineNumbers.WriteString(cursorStyle.Render(fmt.Sprintf("%3d", i)) + "\n")
+			continue
+		}
 		lineNumbers.WriteString(fmt.Sprintf("%3d\n", i))
 	}
 	m.LineNumbers.SetContent(lineNumbers.String() + "  ~\n")
@@ -409,12 +612,24 @@ func (m *Model) updateActivePane(msg tea.Msg) tea.Cmd {
 	m.updateStyleByPane()
 
 	switch m.pane {
+	case folderPane:
+		m.Folders, cmd = m.Folders.Update(msg)
+		cmds = append(cmds, cmd)
+		// the folder drives the snippet list, it is saved on exit
+		if folder := string(m.selectedFolder()); folder != m.config.FolderName {
+			m.config.FolderName = folder
+			cmds = append(cmds, m.updateContent())
+		}
 	case snippetPane:
 		*m.Snippets(), cmd = (*m.Snippets()).Update(msg)
 		cmds = append(cmds, cmd, m.updateContent())
 	case sectionPane:
+		selected := m.selectedSection().Slug
 		*m.Sections(), cmd = (*m.Sections()).Update(msg)
 		cmds = append(cmds, cmd)
+		if m.selectedSection().Slug != selected {
+			cmds = append(cmds, m.scheduleSectionOpen())
+		}
 	case contentPane:
 		m.Code, cmd = m.Code.Update(msg)
 		cmds = append(cmds, cmd)
@@ -422,6 +637,7 @@ func (m *Model) updateActivePane(msg tea.Msg) tea.Cmd {
 		cmds = append(cmds, cmd)
 	}
 
+	m.Folders.SetDelegate(folderDelegate{m.FolderStyle})
 	m.Snippets().SetDelegate(snippetDelegate{m.pane, m.SnippetStyle, m.state})
 	m.Sections().SetDelegate(sectionDelegate{m.pane, m.SectionStyle, m.state})
 
@@ -432,9 +648,8 @@ func (m *Model) updateActivePane(msg tea.Msg) tea.Cmd {
 // snippet list.
 func (m *Model) updateKeyMap() {
 	hasItems := len(m.Snippets().VisibleItems()) > 0
-	isFiltering := m.Snippets().FilterState() == list.Filtering
 	isEditing := m.state == editingState
-	m.keys.EditSnippet.SetEnabled(hasItems && !isFiltering && !isEditing)
+	m.keys.EditSnippet.SetEnabled(hasItems && !isEditing)
 }
 
 // selected folder returns the currently selected folder.
@@ -472,11 +687,11 @@ func (m *Model) Snippets() *list.Model {
 // Sections returns the active list.
 func (m *Model) Sections() *list.Model {
 	snippet := m.selectedSnippet()
-	if sections, ok := m.SectionsMap[snippet]; ok {
+	if sections, ok := m.SectionsMap[snippet.key()]; ok {
 		return sections
 	}
 	m.updateSnippetSections(snippet)
-	return m.SectionsMap[snippet]
+	return m.SectionsMap[snippet.key()]
 }
 
 func (m *Model) moveSnippetDown() {
@@ -500,11 +715,22 @@ func (m *Model) View() string {
 	if m.state == quittingState {
 		return ""
 	}
+	if m.overlay != nil {
+		return m.overlay.View()
+	}
 
 	snippetList := m.Snippets()
 	sectionList := m.Sections()
 	selectedSnippet := m.selectedSnippet()
-	snippetTitleBar := m.SnippetStyle.TitleBar.Render("Snippets")
+	snippetTitle := "Snippets"
+	if m.tag != "" {
+		snippetTitle += " #" + m.tag
+	}
+	if m.sortFrecent {
+		snippetTitle += " • frecent"
+	}
+	folderTitleBar := m.FolderStyle.TitleBar.Render("Folders")
+	snippetTitleBar := m.SnippetStyle.TitleBar.Render(snippetTitle)
 	sectionTitleBar := m.SectionStyle.TitleBar.Render(selectedSnippet.Name)
 	contentTitleBar := m.ContentStyle.TitleBar.Render("Content")
 
@@ -513,27 +739,49 @@ func (m *Model) View() string {
 		sectionTitleBar = m.SectionStyle.TitleBar.Render(detailTitle)
 	}
 
-	if m.pane == snippetPane {
-		if m.state == copyingState {
+	errTitle := truncate.Truncate("Error: "+m.errMsg, m.config.SnippetTitleBarWidth-2, "...", truncate.PositionEnd)
+
+	if m.pane == folderPane {
+		if m.state == errorState {
+			folderTitleBar = m.FolderStyle.ErrorTitleBar.Render(truncate.Truncate("Error: "+m.errMsg, m.config.FolderTitleBarWidth-2, "...", truncate.PositionEnd))
+		} else if m.state == copyingState {
+			folderTitleBar = m.FolderStyle.CopiedTitleBar.Render("Copied")
+		}
+	} else if m.pane == snippetPane {
+		if m.state == errorState {
+			snippetTitleBar = m.SnippetStyle.ErrorTitleBar.Render(errTitle)
+		} else if m.state == copyingState {
 			snippetTitleBar = m.SnippetStyle.CopiedTitleBar.Render("Copied")
-		} else if snippetList.SettingFilter() {
-			snippetTitleBar = m.SnippetStyle.TitleBar.Render(snippetList.FilterInput.View())
 		}
 	} else if m.pane == sectionPane {
-		if m.state == copyingState {
+		if m.state == errorState {
+			sectionTitleBar = m.SectionStyle.ErrorTitleBar.Render(errTitle)
+		} else if m.state == copyingState {
 			sectionTitleBar = m.SectionStyle.CopiedTitleBar.Render("Copied")
-		} else if sectionList.SettingFilter() {
-			sectionTitleBar = m.SectionStyle.TitleBar.Render(sectionList.FilterInput.View())
 		}
 	} else if m.pane == contentPane {
-		if m.state == copyingState {
+		if m.state == errorState {
+			contentTitleBar = m.ContentStyle.ErrorTitleBar.Render("Error: " + m.errMsg)
+		} else if m.state == copyingState {
 			contentTitleBar = m.ContentStyle.CopiedTitleBar.Render("Copied")
 		}
 	}
+	if m.run != nil && m.run.visible && m.state != copyingState && m.state != errorState {
+		contentTitleBar = m.ContentStyle.TitleBar.Render(m.run.status())
+	} else if m.hints.active && m.state == navigatingState {
+		contentTitleBar = m.ContentStyle.TitleBar.Render("Copy block " + m.hints.typed)
+	} else if m.find.active() && m.state == navigatingState {
+		contentTitleBar = m.ContentStyle.TitleBar.Render(m.findStatus())
+	} else if m.blocks.active && m.state == navigatingState {
+		contentTitleBar = m.ContentStyle.TitleBar.Render(fmt.Sprintf("Block %d/%d", m.blocks.index+1, len(m.blocks.lines)))
+	}
 
 	var components []string
 	if !m.hideSnippetPane {
-		components = append(components, m.SnippetStyle.Base.Render(snippetTitleBar+snippetList.View()))
+		components = append(components,
+			m.FolderStyle.Base.Render(folderTitleBar+m.Folders.View()),
+			m.SnippetStyle.Base.Render(snippetTitleBar+snippetList.View()),
+		)
 	}
 	components = append(components,
 		m.SectionStyle.Base.Render(sectionTitleBar+sectionList.View()),
@@ -560,10 +808,26 @@ func (m *Model) handleCodeBlockBorder(content string, section Section) string {
 	copyKeys := m.config.CopyContentKeys
 	copyKeysIndex := 0
 
-	// handle prefix
-	for _, codeBlock := range section.CodeBlocks {
+	// handle prefix, in the order the code blocks are rendered
+	for _, i := range section.renderedBlocks() {
+		if i < 0 || i >= len(section.CodeBlocks) {
+			// an indented code block has no meta and is never copied
+			s = strings.Replace(s, m.config.CodeBlockPrefixTemp, defaultBorder, 1)
+			continue
+		}
+		codeBlock := section.CodeBlocks[i]
 		prefix := ""
 
+		// handle hint label
+		if m.hints.active {
+			prefix = defaultBorder
+			if label, ok := m.hints.label(i); ok {
+				prefix = m.hintBorder(label)
+			}
+			s = strings.Replace(s, m.config.CodeBlockPrefixTemp, prefix, 1)
+			continue
+		}
+
 		// handle copy title
 		_, copyable := codeBlock.Meta[metaKeyCopyable]
 		if copyable {
@@ -621,20 +885,18 @@ func (m *Model) paddingBorderWithTitle(title string) string {
 type MarkdownElem struct {
 	FirstTitle string
 	CodeBlocks []CodeBlock
+	// Tags are the tags of the {tags="k8s,debug"} heading attributes.
+	Tags []string
+	// attributes are the segments of the heading attributes in the source.
+	attributes []text.Segment
+	// blockOrder is the index in CodeBlocks of every rendered code block, -1
+	// for an indented code block.
+	blockOrder []int
 }
 
-func (m *Model) parseMarkdown(source string) (*MarkdownElem, error) {
-	mdElem := &MarkdownElem{
-		CodeBlocks: make([]CodeBlock, 0),
-	}
-
-	// create parser
-	md := goldmark.New()
-	reader := text.NewReader([]byte(source))
-	doc := md.Parser().Parse(reader)
-
-	// get first title and code block
-	firstTitle := ""
+// collect adds the first title, the code blocks and the heading tags of the
+// node to the markdown elements.
+func (mdElem *MarkdownElem) collect(n ast.Node, source []byte) error {
 	var walker = func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
 			return ast.WalkContinue, nil
@@ -642,24 +904,39 @@ func (m *Model) parseMarkdown(source string) (*MarkdownElem, error) {
 
 		switch node := n.(type) {
 		case *ast.Heading:
-			if firstTitle == "" {
-				title := string(node.Text(reader.Source()))
-				firstTitle = title
-				mdElem.FirstTitle = title
+			if mdElem.FirstTitle == "" {
+				mdElem.FirstTitle = string(node.Text(source))
 			}
+			if node.Attributes() != nil && node.Lines().Len() > 0 {
+				if tags, ok := node.AttributeString(headingAttributeTags); ok {
+					if b, ok := tags.([]byte); ok {
+						mdElem.Tags = appendTags(mdElem.Tags, strings.Split(string(b), ",")...)
+					}
+				}
+				// the attribute follows the text of the heading
+				stop := node.Lines().At(node.Lines().Len() - 1).Stop
+				end := stop + bytes.IndexByte(source[stop:], '\n')
+				if end < stop {
+					end = len(source)
+				}
+				mdElem.attributes = append(mdElem.attributes, text.NewSegment(stop, end))
+			}
+		case *ast.CodeBlock:
+			mdElem.blockOrder = append(mdElem.blockOrder, -1)
 		case *ast.FencedCodeBlock:
+			mdElem.blockOrder = append(mdElem.blockOrder, len(mdElem.CodeBlocks))
 			var content bytes.Buffer
 			lines := node.Lines()
 			for i := 0; i < lines.Len(); i++ {
 				line := lines.At(i)
-				content.Write(line.Value(reader.Source()))
+				content.Write(line.Value(source))
 			}
 
 			// 获取代码内容
 			codeContent := strings.TrimSuffix(content.String(), "\n")
 
 			// 解析信息字符串
-			info := string(node.Info.Text(reader.Source()))
+			info := string(node.Info.Text(source))
 			language, meta := parseCodeBlockInfo(info)
 
 			// 创建新的代码块
@@ -667,17 +944,14 @@ func (m *Model) parseMarkdown(source string) (*MarkdownElem, error) {
 				Content:  codeContent,
 				Language: language,
 				Meta:     meta,
+				Index:    len(mdElem.CodeBlocks) + 1,
 			}
 
 			mdElem.CodeBlocks = append(mdElem.CodeBlocks, codeBlock)
 		}
 		return ast.WalkContinue, nil
 	}
-	if err := ast.Walk(doc, walker); err != nil {
-		return nil, err
-	}
-
-	return mdElem, nil
+	return ast.Walk(n, walker)
 }
 
 // parseCodeBlockInfo Created by claude-3.5-sonnet
@@ -746,7 +1020,13 @@ func parseCodeBlockInfo(info string) (string, map[string]string) {
 }
 
 func (m *Model) updateStyleByPane() {
+	m.FolderStyle = DefaultStyles(m.config).Folders.Blurred
 	switch m.pane {
+	case folderPane:
+		m.FolderStyle = DefaultStyles(m.config).Folders.Focused
+		m.SnippetStyle = DefaultStyles(m.config).Snippets.Blurred
+		m.SectionStyle = DefaultStyles(m.config).Sections.Blurred
+		m.ContentStyle = DefaultStyles(m.config).Content.Blurred
 	case snippetPane:
 		m.SnippetStyle = DefaultStyles(m.config).Snippets.Focused
 		m.SectionStyle = DefaultStyles(m.config).Sections.Blurred
@@ -766,6 +1046,8 @@ func (m *Model) defaultPane() pane {
 	switch m.config.DefaultPane {
 	case "content":
 		return contentPane
+	case "folder":
+		return folderPane
 	case "snippet":
 		return snippetPane
 	default:
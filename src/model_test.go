@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFolderPaneCopyKeys(t *testing.T) {
+	config := newTestRepo(t, map[string]string{"k8s/pods.md": "# Pods\n", "team/deploy.md": "# Deploy\n"})
+	config.FolderName = "team"
+	config.ClipboardBackend = clipboardStdout
+	m := newModel(config, loadSnippets(config), Snippet{})
+	m.Init()
+	m.Update(tea.WindowSizeMsg{Width: 160, Height: 30})
+	m.pane = folderPane
+	if got := m.selectedFolder(); got != "team" {
+		t.Fatalf("selected folder = %q, want team", got)
+	}
+
+	// g moves the folder list to the first folder instead of copying
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if got := m.selectedFolder(); got != "k8s" {
+		t.Errorf("selected folder after g = %q, want k8s", got)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.output != "" {
+		t.Errorf("copy key in the folder pane copied %q", m.output)
+	}
+
+	m.pane = snippetPane
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.output != "# Pods\n" {
+		t.Errorf("copy key in the snippet pane copied %q, want the snippet", m.output)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	overlayStyle      = lipgloss.NewStyle().Margin(1, 2)
+	overlayTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("170"))
+	overlayBodyStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	overlayHelpStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// overlay is a modal component drawn instead of the panes. It receives all
+// key presses while it is open.
+type overlay interface {
+	Update(msg tea.Msg) (overlay, tea.Cmd)
+	View() string
+}
+
+// overlayClosedMsg tells the application to close the overlay.
+type overlayClosedMsg struct{}
+
+// closeOverlay is a Cmd to close the overlay.
+func closeOverlay() tea.Msg {
+	return overlayClosedMsg{}
+}
+
+// promptOverlay asks for a single line of text.
+type promptOverlay struct {
+	title    string
+	input    textinput.Model
+	onSubmit func(value string) tea.Msg
+}
+
+func newPromptOverlay(title, value string, onSubmit func(value string) tea.Msg) *promptOverlay {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.PromptStyle = overlayTitleStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(value)
+	input.Focus()
+	return &promptOverlay{title: title, input: input, onSubmit: onSubmit}
+}
+
+func (p *promptOverlay) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return p, closeOverlay
+		case "enter":
+			value := strings.TrimSpace(p.input.Value())
+			if value == "" {
+				return p, nil
+			}
+			return p, func() tea.Msg { return p.onSubmit(value) }
+		}
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
+
+func (p *promptOverlay) View() string {
+	var b strings.Builder
+	b.WriteString(overlayTitleStyle.Render(p.title) + "\n\n")
+	b.WriteString(p.input.View() + "\n\n")
+	b.WriteString(overlayHelpStyle.Render("enter confirm • esc cancel"))
+	return overlayStyle.Render(b.String())
+}
+
+// confirmOverlay asks a yes or no question.
+type confirmOverlay struct {
+	title     string
+	body      string
+	onConfirm tea.Msg
+}
+
+func (c *confirmOverlay) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "n", "q", "ctrl+c":
+			return c, closeOverlay
+		case "enter", "y":
+			return c, func() tea.Msg { return c.onConfirm }
+		}
+	}
+	return c, nil
+}
+
+func (c *confirmOverlay) View() string {
+	var b strings.Builder
+	b.WriteString(overlayTitleStyle.Render(c.title) + "\n\n")
+	if c.body != "" {
+		b.WriteString(overlayBodyStyle.Render(c.body) + "\n\n")
+	}
+	b.WriteString(overlayHelpStyle.Render("enter/y confirm • esc/n cancel"))
+	return overlayStyle.Render(b.String())
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	metaKeyVars = "vars"
+
+	varsFileName = "vars.json"
+	// the remembered values may be secrets, so only the user can read them.
+	varsFileMode = 0o600
+)
+
+// placeholderPattern matches {{name}}, {{name=default}} and {{name=a|b|c}}.
+// A name starts with a letter or "_", so Go templates such as
+// {{ .Values.image }} are left alone. A match after "$", like the GitHub
+// Actions ${{ secrets.TOKEN }}, is not a placeholder either.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][\w.-]*)\s*(?:=([^}]*))?\}\}`)
+
+var (
+	placeholderLabelStyle   = lipgloss.NewStyle().Width(16).Foreground(lipgloss.Color("252"))
+	placeholderFocusedStyle = lipgloss.NewStyle().Width(16).Foreground(lipgloss.Color("170"))
+	placeholderChoiceStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	placeholderPreviewStyle = lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder()).
+				BorderForeground(lipgloss.Color("241")).
+				Padding(0, 1)
+)
+
+// placeholder is a variable of a code block that is filled in before the
+// block is copied. The first choice is the default when no default is given.
+type placeholder struct {
+	Name    string
+	Default string
+	Choices []string
+}
+
+// parsePlaceholderSpec parses name, name=default and name=a|b|c.
+func parsePlaceholderSpec(spec string) placeholder {
+	name, value, _ := strings.Cut(spec, "=")
+	p := placeholder{Name: strings.TrimSpace(name)}
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "|") {
+		for _, choice := range strings.Split(value, "|") {
+			if choice = strings.TrimSpace(choice); choice != "" {
+				p.Choices = append(p.Choices, choice)
+			}
+		}
+		if len(p.Choices) > 0 {
+			p.Default = p.Choices[0]
+		}
+		return p
+	}
+	p.Default = value
+	return p
+}
+
+// parsePlaceholders returns the placeholders declared by the vars meta and by
+// {{name}} in the code block content, in order of appearance. The vars=false
+// meta turns them off, for templates such as Helm charts that use {{name}}.
+func parsePlaceholders(codeBlock CodeBlock) []placeholder {
+	vars := codeBlock.Meta[metaKeyVars]
+	if vars == "false" {
+		return nil
+	}
+
+	var placeholders []placeholder
+	seen := make(map[string]int)
+	add := func(p placeholder) {
+		if p.Name == "" {
+			return
+		}
+		if i, ok := seen[p.Name]; ok {
+			// a later declaration may add the default or choices
+			if placeholders[i].Default == "" {
+				placeholders[i].Default = p.Default
+			}
+			if len(placeholders[i].Choices) == 0 {
+				placeholders[i].Choices = p.Choices
+			}
+			return
+		}
+		seen[p.Name] = len(placeholders)
+		placeholders = append(placeholders, p)
+	}
+
+	for _, spec := range varSpecs(vars) {
+		add(parsePlaceholderSpec(spec))
+	}
+	content := codeBlock.Content
+	for _, match := range placeholderMatches(content) {
+		spec := content[match[2]:match[3]]
+		if match[4] >= 0 {
+			spec += "=" + content[match[4]:match[5]]
+		}
+		add(parsePlaceholderSpec(spec))
+	}
+	return placeholders
+}
+
+// placeholderMatches returns the submatch indexes of the {{name}}
+// placeholders in the content, skipping the ${{ }} expressions.
+func placeholderMatches(content string) [][]int {
+	var matches [][]int
+	for _, match := range placeholderPattern.FindAllStringSubmatchIndex(content, -1) {
+		if match[0] > 0 && content[match[0]-1] == '$' {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// varSpecs returns the placeholder specs of the vars meta, the bare {vars}
+// and vars=false declare none.
+func varSpecs(vars string) []string {
+	if vars == "" || vars == "true" || vars == "false" {
+		return nil
+	}
+	return strings.Split(vars, ",")
+}
+
+// fillPlaceholders replaces {{name}} and the <name> of the vars meta with the
+// values. The content of a code block with vars=false is kept as is.
+func fillPlaceholders(codeBlock CodeBlock, values map[string]string) string {
+	vars := codeBlock.Meta[metaKeyVars]
+	if vars == "false" {
+		return codeBlock.Content
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, match := range placeholderMatches(codeBlock.Content) {
+		value, ok := values[codeBlock.Content[match[2]:match[3]]]
+		if !ok {
+			continue
+		}
+		b.WriteString(codeBlock.Content[pos:match[0]])
+		b.WriteString(value)
+		pos = match[1]
+	}
+	b.WriteString(codeBlock.Content[pos:])
+	content := b.String()
+
+	for _, spec := range varSpecs(vars) {
+		name := parsePlaceholderSpec(spec).Name
+		if value, ok := values[name]; ok && name != "" {
+			content = strings.ReplaceAll(content, "<"+name+">", value)
+		}
+	}
+	return content
+}
+
+// readVars reads the placeholder values remembered from last time.
+func readVars(config Config) map[string]string {
+	vars := make(map[string]string)
+	b, err := os.ReadFile(filepath.Join(config.Home, varsFileName))
+	if err != nil {
+		return vars
+	}
+	_ = json.Unmarshal(b, &vars)
+	return vars
+}
+
+// writeVars remembers the placeholder values for next time.
+func writeVars(config Config, values map[string]string) error {
+	vars := readVars(config)
+	for name, value := range values {
+		vars[name] = value
+	}
+
+	b, err := json.MarshalIndent(vars, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to serialize vars: %w", err)
+	}
+	b = append(b, '\n')
+
+	file := filepath.Join(config.Home, varsFileName)
+	if err = os.WriteFile(file, b, varsFileMode); err != nil {
+		return fmt.Errorf("unable to write vars file %s: %w", file, err)
+	}
+	return nil
+}
+
+// placeholderAction is what happens with the content once the placeholders
+// are filled in.
+type placeholderAction int
+
+const (
+	copyAction placeholderAction = iota
+	copyExitAction
+	runAction
+)
+
+// placeholdersFilledMsg is sent when the placeholder form is submitted.
+type placeholdersFilledMsg struct {
+	content string
+	block   int
+	values  map[string]string
+	action  placeholderAction
+}
+
+// placeholderForm is the overlay asking for the placeholder values of a code
+// block before it is copied.
+type placeholderForm struct {
+	codeBlock    CodeBlock
+	placeholders []placeholder
+	inputs       []textinput.Model
+	focus        int
+	action       placeholderAction
+}
+
+func newPlaceholderForm(codeBlock CodeBlock, placeholders []placeholder, remembered map[string]string, action placeholderAction) *placeholderForm {
+	f := &placeholderForm{
+		codeBlock:    codeBlock,
+		placeholders: placeholders,
+		action:       action,
+	}
+	for _, p := range placeholders {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Placeholder = strings.Join(p.Choices, " | ")
+		input.Cursor.SetMode(cursor.CursorStatic)
+		value := p.Default
+		if v, ok := remembered[p.Name]; ok && v != "" {
+			value = v
+		}
+		input.SetValue(value)
+		f.inputs = append(f.inputs, input)
+	}
+	f.inputs[0].Focus()
+	return f
+}
+
+// values returns the current value of every placeholder.
+func (f *placeholderForm) values() map[string]string {
+	values := make(map[string]string, len(f.inputs))
+	for i, p := range f.placeholders {
+		values[p.Name] = f.inputs[i].Value()
+	}
+	return values
+}
+
+// setFocus moves the focus to the input at index i.
+func (f *placeholderForm) setFocus(i int) {
+	f.inputs[f.focus].Blur()
+	f.focus = (i + len(f.inputs)) % len(f.inputs)
+	f.inputs[f.focus].Focus()
+}
+
+// cycleChoice sets the focused input to the next or previous choice.
+func (f *placeholderForm) cycleChoice(step int) {
+	choices := f.placeholders[f.focus].Choices
+	if len(choices) == 0 {
+		return
+	}
+	current := 0
+	for i, choice := range choices {
+		if choice == f.inputs[f.focus].Value() {
+			current = i
+			break
+		}
+	}
+	next := (current + step + len(choices)) % len(choices)
+	f.inputs[f.focus].SetValue(choices[next])
+}
+
+func (f *placeholderForm) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return f, closeOverlay
+		case "tab", "down":
+			f.setFocus(f.focus + 1)
+			return f, nil
+		case "shift+tab", "up":
+			f.setFocus(f.focus - 1)
+			return f, nil
+		case "ctrl+n":
+			f.cycleChoice(1)
+			return f, nil
+		case "ctrl+p":
+			f.cycleChoice(-1)
+			return f, nil
+		case "enter":
+			if f.focus < len(f.inputs)-1 {
+				f.setFocus(f.focus + 1)
+				return f, nil
+			}
+			values := f.values()
+			content := fillPlaceholders(f.codeBlock, values)
+			block, action := f.codeBlock.Index, f.action
+			return f, func() tea.Msg {
+				return placeholdersFilledMsg{content: content, block: block, values: values, action: action}
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return f, cmd
+}
+
+func (f *placeholderForm) View() string {
+	var b strings.Builder
+	b.WriteString(overlayTitleStyle.Render("Fill in the placeholders") + "\n\n")
+
+	for i, p := range f.placeholders {
+		label := placeholderLabelStyle.Render(p.Name)
+		if i == f.focus {
+			label = placeholderFocusedStyle.Render("> " + p.Name)
+		}
+		line := label + f.inputs[i].View()
+		if len(p.Choices) > 0 && f.inputs[i].Value() != "" {
+			line += placeholderChoiceStyle.Render("  (" + strings.Join(p.Choices, " | ") + ")")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + placeholderPreviewStyle.Render(fillPlaceholders(f.codeBlock, f.values())) + "\n\n")
+	submit := "copy"
+	if f.action == runAction {
+		submit = "run"
+	}
+	b.WriteString(overlayHelpStyle.Render("tab/↓ next • shift+tab/↑ prev • ctrl+n/ctrl+p choice • enter " + submit + " • esc cancel"))
+	return overlayStyle.Render(b.String())
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		meta    map[string]string
+		want    []placeholder
+	}{
+		{
+			name:    "body placeholder without meta",
+			content: "kubectl -n {{namespace}} get pods",
+			want:    []placeholder{{Name: "namespace"}},
+		},
+		{
+			name:    "default and choices",
+			content: "kubectl logs --since={{ since=1h }} --context={{env=dev|staging|prod}}",
+			want: []placeholder{
+				{Name: "since", Default: "1h"},
+				{Name: "env", Default: "dev", Choices: []string{"dev", "staging", "prod"}},
+			},
+		},
+		{
+			name:    "declared in the vars meta",
+			content: "kubectl -n <namespace> logs <pod>",
+			meta:    map[string]string{metaKeyVars: "namespace=default,pod"},
+			want:    []placeholder{{Name: "namespace", Default: "default"}, {Name: "pod"}},
+		},
+		{
+			name:    "meta and body merged",
+			content: "kubectl -n {{namespace}} logs {{pod=web}} {{namespace}}",
+			meta:    map[string]string{metaKeyVars: "namespace=default,pod"},
+			want:    []placeholder{{Name: "namespace", Default: "default"}, {Name: "pod", Default: "web"}},
+		},
+		{
+			name:    "bare vars meta",
+			content: "echo {{msg}}",
+			meta:    map[string]string{metaKeyVars: "true"},
+			want:    []placeholder{{Name: "msg"}},
+		},
+		{
+			name:    "go template",
+			content: "image: {{ .Values.image }}",
+		},
+		{
+			name:    "github actions expression",
+			content: "run: echo ${{ secrets.TOKEN }} {{ tag }}",
+			want:    []placeholder{{Name: "tag"}},
+		},
+		{
+			name:    "turned off",
+			content: "{{ if .Values.enabled }}{{ end }} {{name}}",
+			meta:    map[string]string{metaKeyVars: "false"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePlaceholders(CodeBlock{Content: tt.content, Meta: tt.meta})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePlaceholders(%q) = %+v, want %+v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillPlaceholders(t *testing.T) {
+	values := map[string]string{"namespace": "kube-system", "pod": "coredns", "env": "prod"}
+	tests := []struct {
+		name    string
+		content string
+		meta    map[string]string
+		want    string
+	}{
+		{
+			name:    "body placeholders",
+			content: "kubectl -n {{namespace}} --context={{ env=dev|prod }} get pods",
+			want:    "kubectl -n kube-system --context=prod get pods",
+		},
+		{
+			name:    "meta placeholders",
+			content: "kubectl -n <namespace> logs <pod>",
+			meta:    map[string]string{metaKeyVars: "namespace,pod"},
+			want:    "kubectl -n kube-system logs coredns",
+		},
+		{
+			name:    "angle brackets need the meta",
+			content: "kubectl -n <namespace> logs {{pod}}",
+			want:    "kubectl -n <namespace> logs coredns",
+		},
+		{
+			name:    "unknown names are kept",
+			content: "echo {{namespace}} {{missing}}",
+			want:    "echo kube-system {{missing}}",
+		},
+		{
+			name:    "templates are kept",
+			content: "{{ .Values.pod }} ${{ namespace }} {{pod}}",
+			want:    "{{ .Values.pod }} ${{ namespace }} coredns",
+		},
+		{
+			name:    "turned off",
+			content: "{{ namespace }} <pod>",
+			meta:    map[string]string{metaKeyVars: "false"},
+			want:    "{{ namespace }} <pod>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fillPlaceholders(CodeBlock{Content: tt.content, Meta: tt.meta}, values); got != tt.want {
+				t.Errorf("fillPlaceholders(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
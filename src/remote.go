@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	githubHost = "github.com"
+	// localHost is the host segment of the names of local repos.
+	localHost = "local"
+)
+
+// scpLikePattern matches the scp-like syntax of ssh remotes, [user@]host:path.
+var scpLikePattern = regexp.MustCompile(`^(?:[\w.+-]+@)?([\w.-]+):(.+)$`)
+
+// remote is a parsed git remote URL.
+type remote struct {
+	// Host is empty for local repos.
+	Host string
+	// Path is the slash separated repo path without the .git suffix, it may
+	// include nested groups.
+	Path     string
+	CloneURL string
+}
+
+// Name returns the repo name, which is also the directory in the repo base.
+// GitHub repos keep the <user>/<repo> name, other repos are stored under
+// <host>/<path>, and local repos under local/<path>.
+func (r remote) Name() string {
+	switch {
+	case r.Host == "":
+		return localHost + "/" + r.Path
+	case r.Host == githubHost && strings.Count(r.Path, "/") == 1:
+		return r.Path
+	}
+	return r.Host + "/" + r.Path
+}
+
+// parseRemoteURL parses ssh://, git://, http(s)://, file:// and scp-like
+// [user@]host:path URLs, local paths, and the GitHub <user>/<repo> shorthand.
+func parseRemoteURL(repoURL string) (remote, error) {
+	repoURL = strings.TrimSpace(repoURL)
+	r := remote{CloneURL: repoURL}
+
+	switch {
+	case strings.Contains(repoURL, "://"):
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return remote{}, fmt.Errorf("invalid repository URL %s: %w", repoURL, err)
+		}
+		switch u.Scheme {
+		case "ssh", "git+ssh", "ssh+git", "git", "http", "https":
+			if u.Hostname() == "" {
+				return remote{}, fmt.Errorf("invalid repository URL %s: missing host", repoURL)
+			}
+			r.Host = strings.ToLower(u.Hostname())
+		case "file":
+		default:
+			return remote{}, fmt.Errorf("invalid repository URL %s: unsupported scheme %s", repoURL, u.Scheme)
+		}
+		r.Path = u.Path
+	case filepath.IsAbs(repoURL) || strings.HasPrefix(repoURL, "."):
+		abs, err := filepath.Abs(repoURL)
+		if err != nil {
+			return remote{}, fmt.Errorf("invalid repository path %s: %w", repoURL, err)
+		}
+		r.CloneURL = abs
+		r.Path = filepath.ToSlash(strings.TrimPrefix(abs, filepath.VolumeName(abs)))
+	case scpLikePattern.MatchString(repoURL):
+		match := scpLikePattern.FindStringSubmatch(repoURL)
+		r.Host = strings.ToLower(match[1])
+		r.Path = match[2]
+	default:
+		// <user>/<repo> on GitHub
+		if strings.Count(strings.Trim(repoURL, "/"), "/") != 1 {
+			return remote{}, fmt.Errorf("invalid repository URL %s, use <user>/<repo> or a git URL", repoURL)
+		}
+		r.Host = githubHost
+		r.Path = repoURL
+		r.CloneURL = githubSSHPrefix + strings.TrimSuffix(strings.Trim(repoURL, "/"), githubSSHSuffix) + githubSSHSuffix
+	}
+
+	r.Path = strings.TrimSuffix(strings.Trim(r.Path, "/"), githubSSHSuffix)
+	r.Path = strings.TrimSuffix(r.Path, "/")
+	if err := checkRepoName(r.Path); err != nil {
+		return remote{}, fmt.Errorf("invalid repository URL %s: %w", repoURL, err)
+	}
+	return r, nil
+}
+
+// checkRepoName returns an error if the name isn't a relative slash separated
+// path in the repo base.
+func checkRepoName(name string) error {
+	if name == "" || name == allReposName {
+		return fmt.Errorf("invalid repo name %q", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || strings.HasPrefix(part, ".") || strings.ContainsAny(part, `\:`) {
+			return fmt.Errorf("invalid repo name %q", name)
+		}
+	}
+	return nil
+}
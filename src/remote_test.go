@@ -0,0 +1,122 @@
+package main
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		host     string
+		path     string
+		name     string
+		cloneURL string
+	}{
+		{
+			url:      "kugarocks/rockman",
+			host:     "github.com",
+			path:     "kugarocks/rockman",
+			name:     "kugarocks/rockman",
+			cloneURL: "git@github.com:kugarocks/rockman.git",
+		},
+		{
+			url:      "kugarocks/rockman.git",
+			host:     "github.com",
+			path:     "kugarocks/rockman",
+			name:     "kugarocks/rockman",
+			cloneURL: "git@github.com:kugarocks/rockman.git",
+		},
+		{
+			url:      "git@github.com:kugarocks/rockman.git",
+			host:     "github.com",
+			path:     "kugarocks/rockman",
+			name:     "kugarocks/rockman",
+			cloneURL: "git@github.com:kugarocks/rockman.git",
+		},
+		{
+			url:      "git@GitLab.com:team/group/notes.git",
+			host:     "gitlab.com",
+			path:     "team/group/notes",
+			name:     "gitlab.com/team/group/notes",
+			cloneURL: "git@GitLab.com:team/group/notes.git",
+		},
+		{
+			url:      "gitlab.example.com:notes",
+			host:     "gitlab.example.com",
+			path:     "notes",
+			name:     "gitlab.example.com/notes",
+			cloneURL: "gitlab.example.com:notes",
+		},
+		{
+			url:      "https://github.com/kugarocks/rockman.git",
+			host:     "github.com",
+			path:     "kugarocks/rockman",
+			name:     "kugarocks/rockman",
+			cloneURL: "https://github.com/kugarocks/rockman.git",
+		},
+		{
+			url:      "https://github.com/org/team/notes/",
+			host:     "github.com",
+			path:     "org/team/notes",
+			name:     "github.com/org/team/notes",
+			cloneURL: "https://github.com/org/team/notes/",
+		},
+		{
+			url:      "ssh://git@git.example.com:2222/group/sub/notes.git",
+			host:     "git.example.com",
+			path:     "group/sub/notes",
+			name:     "git.example.com/group/sub/notes",
+			cloneURL: "ssh://git@git.example.com:2222/group/sub/notes.git",
+		},
+		{
+			url:      "file:///srv/git/notes.git",
+			path:     "srv/git/notes",
+			name:     "local/srv/git/notes",
+			cloneURL: "file:///srv/git/notes.git",
+		},
+		{
+			url:      "/srv/git/notes",
+			path:     "srv/git/notes",
+			name:     "local/srv/git/notes",
+			cloneURL: "/srv/git/notes",
+		},
+		{
+			url:      "file:///notes.git",
+			path:     "notes",
+			name:     "local/notes",
+			cloneURL: "file:///notes.git",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			r, err := parseRemoteURL(tt.url)
+			if err != nil {
+				t.Fatalf("parseRemoteURL(%q) returned error: %v", tt.url, err)
+			}
+			if r.Host != tt.host || r.Path != tt.path || r.CloneURL != tt.cloneURL {
+				t.Errorf("parseRemoteURL(%q) = %+v, want host %q, path %q, clone URL %q", tt.url, r, tt.host, tt.path, tt.cloneURL)
+			}
+			if name := r.Name(); name != tt.name {
+				t.Errorf("parseRemoteURL(%q).Name() = %q, want %q", tt.url, name, tt.name)
+			}
+		})
+	}
+}
+
+func TestParseRemoteURLInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"rockman",
+		"a/b/c",
+		"ftp://example.com/notes.git",
+		"https:///notes.git",
+		"git@github.com:../notes.git",
+		"git@github.com:team/.hidden.git",
+		"file:///",
+	}
+	for _, url := range tests {
+		t.Run(url, func(t *testing.T) {
+			if r, err := parseRemoteURL(url); err == nil {
+				t.Errorf("parseRemoteURL(%q) = %+v, want an error", url, r)
+			}
+		})
+	}
+}
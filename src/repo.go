@@ -2,11 +2,16 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -15,6 +20,10 @@ import (
 
 const (
 	defaultRepoName = "local/repo"
+	// allReposName is the repo name of the All entry, which loads the
+	// snippets of every repo.
+	allReposName  = "*"
+	allReposTitle = "All"
 )
 
 var (
@@ -29,12 +38,17 @@ var (
 type Repo struct {
 	Name string `json:"name"`
 	Url  string `json:"url"`
+	// Path is the directory of a repo registered outside of the repo base,
+	// it is empty for the repos in the repo base.
+	Path string `json:"path,omitempty"`
 }
 
 type RepoWrapper struct {
 	RepoList []Repo `json:"repo_list"`
 }
 
+// readRepos reads the repo configuration. The repo base is only searched for
+// repos when there is no configuration yet, syncRepos searches it every time.
 func readRepos(config Config) ([]Repo, error) {
 	repoDir := config.getRepoBase()
 	if err := os.MkdirAll(repoDir, os.ModePerm); err != nil {
@@ -43,38 +57,120 @@ func readRepos(config Config) ([]Repo, error) {
 
 	configFile := filepath.Join(repoDir, config.RepoConfigFile)
 
+	var wrapper RepoWrapper
 	data, err := os.ReadFile(configFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			wrapper := RepoWrapper{
-				RepoList: []Repo{},
-			}
-
-			b, err := json.MarshalIndent(wrapper, "", "  ")
-			if err != nil {
-				return nil, fmt.Errorf("unable to serialize default configuration: %w", err)
-			}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return nil, fmt.Errorf("unable to parse configuration file %s: %w", configFile, err)
+		}
+	}
 
-			err = os.WriteFile(configFile, b, os.ModePerm)
-			if err != nil {
-				return nil, fmt.Errorf("unable to write configuration file %s: %w", configFile, err)
-			}
+	if wrapper.RepoList == nil {
+		wrapper.RepoList = []Repo{}
+	}
 
-			return wrapper.RepoList, nil
+	if data == nil {
+		wrapper.RepoList = append(wrapper.RepoList, discoverRepos(config, wrapper.RepoList)...)
+		if err := writeRepos(config, wrapper.RepoList); err != nil {
+			return nil, err
 		}
+	}
+
+	return wrapper.RepoList, nil
+}
+
+// syncRepos reads the repo configuration and adds the directories in the
+// repo base that contain a snippet config or a git repo if they are missing.
+// Walking the repo base is too slow for every start, so only the mdf repo
+// commands call it.
+func syncRepos(config Config) ([]Repo, error) {
+	repos, err := readRepos(config)
+	if err != nil {
 		return nil, err
 	}
 
+	found := discoverRepos(config, repos)
+	if len(found) == 0 {
+		return repos, nil
+	}
+	repos = append(repos, found...)
+	if err = writeRepos(config, repos); err != nil {
+		return nil, err
+	}
+	for _, repo := range found {
+		fmt.Fprintf(os.Stderr, "Found repo: %s\n", repo.Name)
+	}
+	return repos, nil
+}
+
+// discoverRepos returns the directories in the repo base that contain a
+// snippet config or a git repo, and aren't in the repos yet.
+func discoverRepos(config Config, repos []Repo) []Repo {
+	known := make(map[string]struct{}, len(repos))
+	for _, repo := range repos {
+		known[repo.Name] = struct{}{}
+	}
+
+	var found []Repo
+	base := config.getRepoBase()
+	// repos removed without deleting their directory are ignored
+	ignored := readIgnoreFile(base, "")
+	_ = filepath.WalkDir(base, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil
+		}
+		if path == base {
+			return nil
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil || strings.HasPrefix(entry.Name(), ".") || ignored.ignored(filepath.ToSlash(rel), true) {
+			return filepath.SkipDir
+		}
+
+		_, configErr := os.Stat(filepath.Join(path, config.SnippetConfigFile))
+		if configErr != nil && !isGitRepo(path) {
+			return nil
+		}
+		name := filepath.ToSlash(rel)
+		if _, ok := known[name]; !ok {
+			url, _ := runGit(path, "remote", "get-url", "origin")
+			found = append(found, Repo{Name: name, Url: url})
+		}
+		// a repo never contains another repo
+		return filepath.SkipDir
+	})
+	return found
+}
+
+// readRepoPaths returns the directories of the repos registered outside of
+// the repo base by name.
+func readRepoPaths(config Config) map[string]string {
 	var wrapper RepoWrapper
-	if err := json.Unmarshal(data, &wrapper); err != nil {
-		return nil, fmt.Errorf("unable to parse configuration file %s: %w", configFile, err)
+	data, err := os.ReadFile(filepath.Join(config.getRepoBase(), config.RepoConfigFile))
+	if err != nil || json.Unmarshal(data, &wrapper) != nil {
+		return nil
 	}
 
-	if wrapper.RepoList == nil {
-		wrapper.RepoList = []Repo{}
+	paths := make(map[string]string)
+	for _, repo := range wrapper.RepoList {
+		if repo.Path != "" {
+			paths[repo.Name] = repo.Path
+		}
 	}
+	return paths
+}
 
-	return wrapper.RepoList, nil
+// reportMissingRepos prints the repos whose directory no longer exists.
+func reportMissingRepos(config Config, repos []Repo) {
+	for _, repo := range repos {
+		repoPath := config.forRepo(repo.Name).getRepoPath()
+		if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Repo %s not found at %s, run mdf repo remove %s to remove it\n", repo.Name, repoPath, repo.Name)
+		}
+	}
 }
 
 func writeRepos(config Config, repos []Repo) error {
@@ -113,6 +209,11 @@ func setRepo(config *Config) error {
 			currentIndex = i
 		}
 	}
+	// the virtual All entry loads the snippets of every repo
+	items = append(items, repoItem{Name: allReposName})
+	if config.RepoName == allReposName {
+		currentIndex = len(items) - 1
+	}
 
 	// 创建列表，设置默认选中项
 	l := list.New(items, repoDelegate{}, 30, 14)
@@ -145,6 +246,14 @@ type repoItem Repo
 
 func (i repoItem) FilterValue() string { return i.Name }
 
+// Title returns the repo name, or All for the virtual All entry.
+func (i repoItem) Title() string {
+	if i.Name == allReposName {
+		return allReposTitle
+	}
+	return i.Name
+}
+
 // 源列表代理
 type repoDelegate struct{}
 
@@ -157,7 +266,7 @@ func (d repoDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		return
 	}
 
-	str := fmt.Sprintf("%d. %s", index+1, i.Name)
+	str := fmt.Sprintf("%d. %s", index+1, i.Title())
 
 	fn := repoItemStyle.Render
 	if index == m.Index() {
@@ -189,7 +298,7 @@ func (m repoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "enter":
 			if i, ok := m.list.SelectedItem().(repoItem); ok {
-				m.choice = i.Name
+				m.choice = i.Title()
 				m.config.RepoName = i.Name
 				err := m.config.writeConfig()
 				if err != nil {
@@ -215,6 +324,123 @@ func (m repoModel) View() string {
 	return "\n" + m.list.View()
 }
 
+// repoSelectedMsg is sent when a repo is picked in the repo switcher.
+type repoSelectedMsg string
+
+// repoSwitcher is the overlay to switch the repo of the running TUI.
+type repoSwitcher struct {
+	repos   []repoItem
+	current string
+	cursor  int
+}
+
+func newRepoSwitcher(repos []Repo, current string) *repoSwitcher {
+	s := &repoSwitcher{current: current}
+	for _, repo := range repos {
+		s.repos = append(s.repos, repoItem(repo))
+	}
+	// the virtual All entry loads the snippets of every repo
+	s.repos = append(s.repos, repoItem{Name: allReposName})
+	for i, repo := range s.repos {
+		if repo.Name == current {
+			s.cursor = i
+		}
+	}
+	return s
+}
+
+func (s *repoSwitcher) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q", "ctrl+c":
+			return s, closeOverlay
+		case "up", "k", "ctrl+p":
+			if s.cursor > 0 {
+				s.cursor--
+			}
+		case "down", "j", "ctrl+n":
+			if s.cursor < len(s.repos)-1 {
+				s.cursor++
+			}
+		case "enter":
+			name := s.repos[s.cursor].Name
+			return s, func() tea.Msg { return repoSelectedMsg(name) }
+		}
+	}
+	return s, nil
+}
+
+func (s *repoSwitcher) View() string {
+	var b strings.Builder
+	b.WriteString(overlayTitleStyle.Render("Switch repo") + "\n\n")
+	for i, repo := range s.repos {
+		line := fmt.Sprintf("%d. %s", i+1, repo.Title())
+		if repo.Name == s.current {
+			line += " •"
+		}
+		if i == s.cursor {
+			b.WriteString(repoSelectedItemStyle.Render("> "+line) + "\n")
+			continue
+		}
+		b.WriteString(repoItemStyle.Render(line) + "\n")
+	}
+	b.WriteString("\n" + overlayHelpStyle.Render("↑/↓ move • enter switch • esc cancel"))
+	return overlayStyle.Render(b.String())
+}
+
+// pickRepo opens the repo switcher.
+func (m *Model) pickRepo() tea.Cmd {
+	repos, err := readRepos(m.config)
+	if err != nil {
+		return m.showError(fmt.Errorf("failed to read repo configuration: %w", err))
+	}
+	current := m.config.RepoName
+	if m.config.isAllRepos() {
+		current = allReposName
+	}
+	m.overlay = newRepoSwitcher(repos, current)
+	return nil
+}
+
+// switchRepo loads the snippets of the repo and rebuilds the folder and the
+// snippet lists. The snippets of the previous repo are saved first, the tag
+// filter and the frecent sort are reset.
+func (m *Model) switchRepo(name string) tea.Cmd {
+	m.overlay = nil
+	config := m.config.forRepo(name)
+	validateRepoName(&config)
+	if config.RepoName != name {
+		return m.showError(fmt.Errorf("repo %s not found", name))
+	}
+
+	m.run.kill()
+	m.run = nil
+	writeSnippets(m.config, m.allSnippets())
+	writeUsage(m.config, m.allSnippets())
+
+	snippets := loadSnippets(config)
+	if len(snippets) == 0 {
+		snippets = append(snippets, defaultSnippet)
+	}
+	next := newModel(config, snippets, Snippet{})
+	m.config = config
+	m.SnippetsMap = next.SnippetsMap
+	m.Folders = next.Folders
+	m.SectionsMap = make(map[string]*list.Model)
+	m.tag, m.hiddenSnippets = "", nil
+	m.sortFrecent, m.snippetOrder = false, nil
+	m.openedSections = nil
+	m.blocks, m.hints, m.find = blockCursor{}, hintMode{}, contentFind{}
+
+	for _, snippetList := range m.SnippetsMap {
+		snippetList.SetHeight(m.height)
+	}
+	m.Folders.SetHeight(m.height)
+	m.updateStyleByPane()
+	m.updateKeyMap()
+	return m.updateContent()
+}
+
 func listRepos(config Config) error {
 	repos, err := readRepos(config)
 	if err != nil {
@@ -228,5 +454,356 @@ func listRepos(config Config) error {
 			fmt.Printf("%s\n", repoItemStyle.Render(repo.Name))
 		}
 	}
+	if config.isAllRepos() {
+		fmt.Printf("%s\n", repoSelectedItemStyle.Render("> "+allReposTitle))
+	}
 	return nil
 }
+
+const repoUsage = `Usage:
+  mdf repo add <path> [--name <name>]  - register an existing directory as a repo without moving it
+  mdf repo update [<name>|--all]  - git pull --ff-only the current, named or every repo
+  mdf repo remove <name>          - remove the repo from repo-config.json, add --delete to delete its directory
+  mdf repo status [<name>]        - show branch, ahead/behind and changed files, add --fetch to fetch first`
+
+// repoStatusRecord is the machine-readable status of a repo.
+type repoStatusRecord struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Git      bool   `json:"git"`
+	Branch   string `json:"branch"`
+	Upstream string `json:"upstream"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	Changed  int    `json:"changed"`
+	Error    string `json:"error,omitempty"`
+}
+
+// runRepo runs the repo update, remove and status commands.
+func runRepo(config Config, args []string) error {
+	parsed := parseArgs(args, "format", "name")
+	if _, err := syncRepos(config); err != nil {
+		return fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+	switch parsed.arg(0) {
+	case "add":
+		return addRepo(config, parsed.arg(1), parsed.get("name"))
+	case "update":
+		return updateRepos(config, parsed.arg(1))
+	case "remove", "rm":
+		return removeRepo(config, parsed.arg(1), parsed.has("delete"))
+	case "status":
+		return repoStatus(config, parsed)
+	}
+	return errors.New(repoUsage)
+}
+
+// addRepo registers an existing directory as a repo. A directory outside of
+// the repo base is kept in place and its path is saved in repo-config.json.
+func addRepo(config Config, dir, name string) error {
+	if dir == "" {
+		return errors.New(repoUsage)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", dir, err)
+	}
+	if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	repo := Repo{Name: filepath.Base(abs), Path: abs}
+	if rel, err := filepath.Rel(config.getRepoBase(), abs); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		repo = Repo{Name: filepath.ToSlash(rel)}
+	}
+	if name != "" && name != repo.Name {
+		repo = Repo{Name: name, Path: abs}
+	}
+	if err = checkRepoName(repo.Name); err != nil {
+		return err
+	}
+	if isGitRepo(abs) {
+		repo.Url, _ = runGit(abs, "remote", "get-url", "origin")
+	}
+
+	repos, err := readRepos(config)
+	if err != nil {
+		return fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+	for _, r := range repos {
+		if r.Name == repo.Name {
+			return fmt.Errorf("repo %s already exists, use --name to choose another name", repo.Name)
+		}
+		if r.Path != "" && r.Path == abs {
+			return fmt.Errorf("%s is already added as repo %s", abs, r.Name)
+		}
+	}
+
+	if err = writeRepos(config, append(repos, repo)); err != nil {
+		return fmt.Errorf("failed to save repo configuration: %w", err)
+	}
+	fmt.Printf("Added repo: %s (%s)\n", repo.Name, abs)
+	return nil
+}
+
+// selectRepos returns the named repo, every repo in all repos mode, or the
+// configured repo.
+func selectRepos(config Config, name string) ([]Repo, error) {
+	repos, err := readRepos(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+	if name == "" && config.isAllRepos() {
+		return repos, nil
+	}
+	if name == "" {
+		name = config.getRepoName()
+	}
+	for _, repo := range repos {
+		if repo.Name == name {
+			return []Repo{repo}, nil
+		}
+	}
+	return nil, fmt.Errorf("repo %s not found", name)
+}
+
+// forEachRepo calls fn for every repo in parallel and waits for all of them.
+func forEachRepo(repos []Repo, fn func(i int, repo Repo)) {
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo Repo) {
+			defer wg.Done()
+			fn(i, repo)
+		}(i, repo)
+	}
+	wg.Wait()
+}
+
+// updateRepos pulls the repos in parallel and prints a summary per repo.
+func updateRepos(config Config, name string) error {
+	repos, err := selectRepos(config, name)
+	if err != nil {
+		return err
+	}
+
+	summaries := make([]string, len(repos))
+	errs := make([]error, len(repos))
+	forEachRepo(repos, func(i int, repo Repo) {
+		summaries[i], errs[i] = updateRepo(config.forRepo(repo.Name).getRepoPath())
+	})
+
+	width := 0
+	for _, repo := range repos {
+		width = max(width, len(repo.Name))
+	}
+	failed := 0
+	for i, repo := range repos {
+		if errs[i] != nil {
+			failed++
+			fmt.Printf("%-*s  failed: %v\n", width, repo.Name, errs[i])
+			continue
+		}
+		fmt.Printf("%-*s  %s\n", width, repo.Name, summaries[i])
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repos failed to update", failed, len(repos))
+	}
+	return nil
+}
+
+// updateRepo fast-forwards the repo in the directory and returns the summary.
+func updateRepo(dir string) (string, error) {
+	if !isGitRepo(dir) {
+		return "skipped, not a git repo", nil
+	}
+	before, after, err := gitPull(dir)
+	if err != nil {
+		return "", err
+	}
+	if before == after {
+		return "already up to date", nil
+	}
+	commits := "commits"
+	count := gitCommitCount(dir, before, after)
+	if count == 1 {
+		commits = "commit"
+	}
+	if before == "" {
+		return fmt.Sprintf("updated to %.7s, %d %s", after, count, commits), nil
+	}
+	return fmt.Sprintf("updated %.7s..%.7s, %d %s", before, after, count, commits), nil
+}
+
+// removeRepo removes the repo from repo-config.json and deletes its directory
+// if asked to.
+func removeRepo(config Config, name string, deleteDir bool) error {
+	if name == "" {
+		return errors.New(repoUsage)
+	}
+	if name == defaultRepoName {
+		return fmt.Errorf("the default repo %s can't be removed", name)
+	}
+
+	repos, err := readRepos(config)
+	if err != nil {
+		return fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+	idx := -1
+	for i, repo := range repos {
+		if repo.Name == name {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("repo %s not found", name)
+	}
+	if err = writeRepos(config, append(repos[:idx:idx], repos[idx+1:]...)); err != nil {
+		return fmt.Errorf("failed to save repo configuration: %w", err)
+	}
+	fmt.Printf("Removed repo: %s\n", name)
+
+	if config.RepoName == name {
+		config.RepoName = defaultRepoName
+		if err = config.writeConfig(); err != nil {
+			return fmt.Errorf("failed to switch to the default repo: %w", err)
+		}
+		fmt.Printf("Switched to repo: %s\n", defaultRepoName)
+	}
+
+	if repos[idx].Path != "" {
+		// a directory registered by mdf repo add is never deleted
+		fmt.Printf("Kept directory: %s\n", repos[idx].Path)
+		return nil
+	}
+	repoPath := config.forRepo(name).getRepoPath()
+	if !deleteDir {
+		if err = ignoreRepoDir(config, name); err != nil {
+			return err
+		}
+		fmt.Printf("Kept directory: %s, add --delete to delete it\n", repoPath)
+		return nil
+	}
+	if err = deleteRepoDir(config, repoPath); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted directory: %s\n", repoPath)
+	return nil
+}
+
+// ignoreRepoDir adds the repo to the .mdfignore file of the repo base, so
+// its kept directory isn't added again.
+func ignoreRepoDir(config Config, name string) error {
+	file := filepath.Join(config.getRepoBase(), ignoreFileName)
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to ignore repo directory: %w", err)
+	}
+	defer f.Close()
+	if _, err = fmt.Fprintf(f, "/%s/\n", name); err != nil {
+		return fmt.Errorf("failed to ignore repo directory: %w", err)
+	}
+	return nil
+}
+
+// deleteRepoDir deletes the repo directory and its empty parents in the repo
+// base directory.
+func deleteRepoDir(config Config, repoPath string) error {
+	base := config.getRepoBase()
+	rel, err := filepath.Rel(base, repoPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("refusing to delete %s outside of %s", repoPath, base)
+	}
+	if err = os.RemoveAll(repoPath); err != nil {
+		return fmt.Errorf("failed to delete repo directory: %w", err)
+	}
+	// os.Remove fails on a non-empty directory, which ends the cleanup
+	for dir := filepath.Dir(repoPath); dir != base; dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// repoStatus prints the branch, ahead/behind counts and changed files of
+// every repo, or of the named repo.
+func repoStatus(config Config, parsed cliArgs) error {
+	format, err := parseListFormat(parsed)
+	if err != nil {
+		return err
+	}
+	repos, err := readRepos(config)
+	if err != nil {
+		return fmt.Errorf("failed to read repo configuration: %w", err)
+	}
+	if name := parsed.arg(1); name != "" {
+		if repos, err = selectRepos(config, name); err != nil {
+			return err
+		}
+	}
+
+	records := make([]repoStatusRecord, len(repos))
+	forEachRepo(repos, func(i int, repo Repo) {
+		dir := config.forRepo(repo.Name).getRepoPath()
+		records[i] = repoStatusRecord{Name: repo.Name, Path: absPath(dir), Git: isGitRepo(dir)}
+		if !records[i].Git {
+			return
+		}
+		if parsed.has("fetch") {
+			if _, err := runGit(dir, "fetch", "--quiet"); err != nil {
+				records[i].Error = err.Error()
+				return
+			}
+		}
+		status, err := readGitStatus(dir)
+		if err != nil {
+			records[i].Error = err.Error()
+		}
+		records[i].Branch = status.Branch
+		records[i].Upstream = status.Upstream
+		records[i].Ahead = status.Ahead
+		records[i].Behind = status.Behind
+		records[i].Changed = status.Changed
+	})
+
+	if format != listFormatText {
+		return writeRecords(os.Stdout, format, records,
+			[]string{"name", "path", "git", "branch", "upstream", "ahead", "behind", "changed", "error"},
+			func(r repoStatusRecord) []string {
+				return []string{r.Name, r.Path, strconv.FormatBool(r.Git), r.Branch, r.Upstream,
+					strconv.Itoa(r.Ahead), strconv.Itoa(r.Behind), strconv.Itoa(r.Changed), r.Error}
+			})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, r := range records {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", r.Name, formatRepoStatus(r))
+	}
+	return w.Flush()
+}
+
+// formatRepoStatus returns the status columns of the text output.
+func formatRepoStatus(r repoStatusRecord) string {
+	switch {
+	case !r.Git:
+		return "not a git repo"
+	case r.Error != "":
+		return "failed: " + r.Error
+	}
+
+	tracking := "no upstream"
+	switch {
+	case r.Upstream == "":
+	case r.Ahead == 0 && r.Behind == 0:
+		tracking = "up to date"
+	default:
+		tracking = fmt.Sprintf("%d ahead, %d behind", r.Ahead, r.Behind)
+	}
+	changes := "clean"
+	if r.Changed > 0 {
+		changes = fmt.Sprintf("%d changed", r.Changed)
+	}
+	return r.Branch + "\t" + tracking + "\t" + changes
+}
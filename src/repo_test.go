@@ -0,0 +1,258 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+// newGitRemote returns a bare repo with one commit, and a clone of it to
+// push more commits from.
+func newGitRemote(t *testing.T) (origin, work string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "mdf")
+	t.Setenv("GIT_AUTHOR_EMAIL", "mdf@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "mdf")
+	t.Setenv("GIT_COMMITTER_EMAIL", "mdf@example.com")
+
+	dir := t.TempDir()
+	origin = filepath.Join(dir, "origin.git")
+	work = filepath.Join(dir, "work")
+	mustGit(t, dir, "init", "--quiet", "--bare", "--initial-branch", "main", origin)
+	mustGit(t, dir, "clone", "--quiet", origin, work)
+	pushCommit(t, work, "k8s/pods.md", "# Pods\n")
+	return origin, work
+}
+
+// pushCommit commits the file in the work clone and pushes it.
+func pushCommit(t *testing.T, work, name, content string) {
+	t.Helper()
+	commitFile(t, work, name, content)
+	mustGit(t, work, "push", "--quiet", "origin", "HEAD:main")
+}
+
+func commitFile(t *testing.T, work, name, content string) {
+	t.Helper()
+	file := filepath.Join(work, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mustGit(t, work, "add", "--all")
+	mustGit(t, work, "commit", "--quiet", "--message", "Update "+name)
+}
+
+func mustGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := runGit(dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func repoNames(repos []Repo) []string {
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Name)
+	}
+	return names
+}
+
+func TestSyncRepos(t *testing.T) {
+	origin, _ := newGitRemote(t)
+	config := newTestRepo(t, map[string]string{"k8s/pods.md": "# Pods\n"})
+	if err := initDefaultRepo(config); err != nil {
+		t.Fatal(err)
+	}
+
+	mustGit(t, config.getRepoBase(), "clone", "--quiet", origin, filepath.Join("team", "notes"))
+	repos, err := readRepos(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := repoNames(repos); slices.Contains(got, "team/notes") {
+		t.Errorf("readRepos = %q, want the repo base not searched", got)
+	}
+
+	if repos, err = syncRepos(config); err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(repos, func(repo Repo) bool { return repo.Name == "team/notes" })
+	if i < 0 {
+		t.Fatalf("syncRepos = %q, want team/notes found", repoNames(repos))
+	}
+	if repos[i].Url != origin {
+		t.Errorf("url of the found repo = %q, want %q", repos[i].Url, origin)
+	}
+	if repos, err = readRepos(config); err != nil || !slices.Contains(repoNames(repos), "team/notes") {
+		t.Errorf("readRepos after syncRepos = %q, %v, want team/notes saved", repoNames(repos), err)
+	}
+}
+
+func TestAddRepo(t *testing.T) {
+	config := newTestRepo(t, nil)
+	vault := filepath.Join(t.TempDir(), "vault")
+	if err := os.Mkdir(vault, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	inBase := filepath.Join(config.getRepoBase(), "team", "docs")
+	if err := os.MkdirAll(inBase, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		as   string
+		want Repo
+		ok   bool
+	}{
+		{"outside of the repo base", vault, "", Repo{Name: "vault", Path: vault}, true},
+		{"added twice", vault, "notes/vault", Repo{}, false},
+		{"in the repo base", inBase, "", Repo{Name: "team/docs"}, true},
+		{"existing name", t.TempDir(), "vault", Repo{}, false},
+		{"invalid name", t.TempDir(), "../vault", Repo{}, false},
+		{"missing directory", filepath.Join(vault, "missing"), "", Repo{}, false},
+	}
+	for _, tt := range tests {
+		err := addRepo(config, tt.dir, tt.as)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: addRepo(%q, %q) = %v, want ok %v", tt.name, tt.dir, tt.as, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		repos, err := readRepos(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(repos, tt.want) {
+			t.Errorf("%s: repos = %+v, want %+v", tt.name, repos, tt.want)
+		}
+	}
+}
+
+func TestUpdateRepos(t *testing.T) {
+	origin, work := newGitRemote(t)
+	config := newTestRepo(t, nil)
+	config.RepoName = "team/notes"
+	dir := config.getRepoPath()
+	mustGit(t, t.TempDir(), "clone", "--quiet", origin, dir)
+	if _, err := syncRepos(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary, err := updateRepo(dir); err != nil || summary != "already up to date" {
+		t.Errorf("updateRepo = %q, %v, want already up to date", summary, err)
+	}
+	pushCommit(t, work, "k8s/logs.md", "# Logs\n")
+	before := gitHead(dir)
+	if err := updateRepos(config, ""); err != nil {
+		t.Fatal(err)
+	}
+	if after := gitHead(dir); after == before {
+		t.Errorf("updateRepos didn't pull the new commit")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "k8s", "logs.md")); err != nil {
+		t.Errorf("pulled file is missing: %v", err)
+	}
+
+	if summary, err := updateRepo(t.TempDir()); err != nil || !strings.HasPrefix(summary, "skipped") {
+		t.Errorf("updateRepo of a plain directory = %q, %v, want skipped", summary, err)
+	}
+	if err := updateRepos(config, "missing/repo"); err == nil {
+		t.Errorf("updateRepos of a missing repo returned no error")
+	}
+}
+
+func TestReadGitStatus(t *testing.T) {
+	origin, work := newGitRemote(t)
+	dir := filepath.Join(t.TempDir(), "notes")
+	mustGit(t, work, "clone", "--quiet", origin, dir)
+
+	status, err := readGitStatus(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (gitStatus{Branch: "main", Upstream: "origin/main"}); status != want {
+		t.Errorf("status of a fresh clone = %+v, want %+v", status, want)
+	}
+
+	pushCommit(t, work, "k8s/logs.md", "# Logs\n")
+	commitFile(t, dir, "k8s/nodes.md", "# Nodes\n")
+	mustGit(t, dir, "fetch", "--quiet")
+	if err = os.WriteFile(filepath.Join(dir, "k8s", "pods.md"), []byte("# Pods\n\nkubectl get pods\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if status, err = readGitStatus(dir); err != nil {
+		t.Fatal(err)
+	}
+	if status.Ahead != 1 || status.Behind != 1 || status.Changed != 1 {
+		t.Errorf("status of a diverged clone = %+v, want 1 ahead, 1 behind and 1 changed", status)
+	}
+}
+
+func TestRemoveRepo(t *testing.T) {
+	origin, _ := newGitRemote(t)
+	config := newTestRepo(t, map[string]string{"k8s/pods.md": "# Pods\n"})
+	if err := initDefaultRepo(config); err != nil {
+		t.Fatal(err)
+	}
+	base := config.getRepoBase()
+	for _, name := range []string{"team/notes", "team/kept"} {
+		mustGit(t, base, "clone", "--quiet", origin, filepath.FromSlash(name))
+	}
+	vault := t.TempDir()
+	if err := addRepo(config, vault, "vault"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncRepos(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := removeRepo(config, defaultRepoName, false); err == nil {
+		t.Errorf("removeRepo of the default repo returned no error")
+	}
+	if err := removeRepo(config, "missing/repo", false); err == nil {
+		t.Errorf("removeRepo of a missing repo returned no error")
+	}
+	for _, name := range []string{"vault", "team/kept"} {
+		if err := removeRepo(config, name, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := removeRepo(config, "team/notes", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(vault); err != nil {
+		t.Errorf("directory of an added repo is deleted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "team", "kept")); err != nil {
+		t.Errorf("kept directory is deleted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "team", "notes")); !os.IsNotExist(err) {
+		t.Errorf("deleted directory still exists: %v", err)
+	}
+	// the kept directory isn't found again
+	repos, err := syncRepos(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := repoNames(repos); !slices.Equal(got, []string{defaultRepoName}) {
+		t.Errorf("repos after remove = %q, want only %s", got, defaultRepoName)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	metaKeyRunnable = "runnable"
+
+	defaultShell = "sh"
+)
+
+var runCommandStyle = lipgloss.NewStyle().
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(lipgloss.Color("241")).
+	Padding(0, 1)
+
+// blockRun holds the output and exit status of a code block run through
+// $SHELL.
+type blockRun struct {
+	cmd      *exec.Cmd
+	output   chan string
+	lines    []string
+	start    time.Time
+	duration time.Duration
+	exitCode int
+	err      error
+	waitErr  error
+	done     bool
+	// visible is false once the content pane shows a section again.
+	visible bool
+}
+
+// runOutputMsg is a line of output of a running code block.
+type runOutputMsg struct {
+	run  *blockRun
+	line string
+}
+
+// runDoneMsg is sent when a running code block exits.
+type runDoneMsg struct {
+	run      *blockRun
+	exitCode int
+	err      error
+}
+
+// runTickMsg updates the run time in the title bar while a code block runs.
+type runTickMsg struct {
+	run *blockRun
+}
+
+// runConfirmedMsg is sent when the run of a code block is confirmed.
+type runConfirmedMsg CodeBlock
+
+// shellCmd returns the command running the content with $SHELL, or sh if no
+// $SHELL is set.
+func shellCmd(content, dir string) *exec.Cmd {
+	shell := strings.TrimSpace(os.Getenv("SHELL"))
+	if shell == "" {
+		shell = defaultShell
+	}
+	cmd := exec.Command(shell, "-c", content)
+	cmd.Dir = dir
+	setProcessGroup(cmd)
+	return cmd
+}
+
+// startBlockRun starts the content in dir, streaming stdout and stderr to the
+// output channel, which is closed when the command exits.
+func startBlockRun(content, dir string) (*blockRun, error) {
+	r := &blockRun{
+		cmd:     shellCmd(content, dir),
+		output:  make(chan string),
+		start:   time.Now(),
+		visible: true,
+	}
+
+	pr, pw := io.Pipe()
+	r.cmd.Stdout = pw
+	r.cmd.Stderr = pw
+	if err := r.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		r.waitErr = r.cmd.Wait()
+		_ = pw.Close()
+	}()
+	go func() {
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			r.output <- scanner.Text()
+		}
+		// keep draining so the command never blocks on a too long line
+		_, _ = io.Copy(io.Discard, pr)
+		close(r.output)
+	}()
+
+	return r, nil
+}
+
+// waitForOutput returns a Cmd waiting for the next line of output, or for
+// the exit of the command once the output is closed.
+func (r *blockRun) waitForOutput() tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-r.output
+		if ok {
+			return runOutputMsg{run: r, line: line}
+		}
+		// the output is closed after Wait returned
+		if r.cmd.ProcessState == nil {
+			return runDoneMsg{run: r, exitCode: -1, err: r.waitErr}
+		}
+		return runDoneMsg{run: r, exitCode: r.cmd.ProcessState.ExitCode(), err: r.waitErr}
+	}
+}
+
+// tick returns a Cmd sending a runTickMsg every second.
+func (r *blockRun) tick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return runTickMsg{run: r}
+	})
+}
+
+// kill stops the command and the processes it started, such as a tail -f or
+// a server, if it is still running.
+func (r *blockRun) kill() {
+	if r != nil && !r.done && r.cmd.Process != nil {
+		_ = killProcessGroup(r.cmd)
+	}
+}
+
+// status returns the run status for the content title bar.
+func (r *blockRun) status() string {
+	if !r.done {
+		return fmt.Sprintf("Running • %s", time.Since(r.start).Round(time.Second))
+	}
+	if r.err != nil {
+		return fmt.Sprintf("Failed • %v", r.err)
+	}
+	return fmt.Sprintf("Exit %d • %s", r.exitCode, r.duration.Round(time.Millisecond))
+}
+
+// runnableCodeBlocks returns the code blocks of the section marked runnable.
+func runnableCodeBlocks(section Section) []CodeBlock {
+	var codeBlocks []CodeBlock
+	for _, codeBlock := range section.CodeBlocks {
+		if _, runnable := codeBlock.Meta[metaKeyRunnable]; runnable {
+			codeBlocks = append(codeBlocks, codeBlock)
+		}
+	}
+	return codeBlocks
+}
+
+// runConfirm is the overlay asking for confirmation before a runnable code
+// block is run.
+type runConfirm struct {
+	codeBlocks []CodeBlock
+	index      int
+	dir        string
+}
+
+func (r *runConfirm) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "n", "q", "ctrl+c":
+			return r, closeOverlay
+		case "tab", "down", "j":
+			r.index = (r.index + 1) % len(r.codeBlocks)
+		case "shift+tab", "up", "k":
+			r.index = (r.index - 1 + len(r.codeBlocks)) % len(r.codeBlocks)
+		case "enter", "y":
+			codeBlock := r.codeBlocks[r.index]
+			return r, func() tea.Msg { return runConfirmedMsg(codeBlock) }
+		}
+	}
+	return r, nil
+}
+
+func (r *runConfirm) View() string {
+	var b strings.Builder
+	title := fmt.Sprintf("Run block %d/%d in %s?", r.index+1, len(r.codeBlocks), r.dir)
+	b.WriteString(overlayTitleStyle.Render(title) + "\n\n")
+	b.WriteString(runCommandStyle.Render(r.codeBlocks[r.index].Content) + "\n\n")
+	b.WriteString(overlayHelpStyle.Render("enter/y run • tab/↓ next block • shift+tab/↑ prev block • esc/n cancel"))
+	return overlayStyle.Render(b.String())
+}
+
+// confirmRun opens the run confirmation for the runnable code blocks of the
+// selected section.
+func (m *Model) confirmRun() tea.Cmd {
+	codeBlocks := runnableCodeBlocks(m.selectedSection())
+	if len(codeBlocks) == 0 {
+		return nil
+	}
+	m.overlay = &runConfirm{codeBlocks: codeBlocks, dir: m.config.getSnippetRepoPath(m.selectedSnippet())}
+	return nil
+}
+
+// startRun runs the content in the directory of the snippet repo and shows the
+// output in the content pane.
+func (m *Model) startRun(content string) tea.Cmd {
+	m.run.kill()
+	r, err := startBlockRun(content, m.config.getSnippetRepoPath(m.selectedSnippet()))
+	if err != nil {
+		r = &blockRun{err: err, done: true, visible: true}
+	}
+	m.run = r
+	m.blocks.active = false
+	m.pane = contentPane
+	m.updateStyleByPane()
+	m.updateRunView()
+	if r.done {
+		return nil
+	}
+	return tea.Batch(r.waitForOutput(), r.tick())
+}
+
+// updateRunOutput handles the output and exit of the running code block. The
+// output of a replaced run is drained and dropped.
+func (m *Model) updateRunOutput(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case runOutputMsg:
+		if msg.run != m.run {
+			return msg.run.waitForOutput()
+		}
+		m.run.lines = append(m.run.lines, msg.line)
+		m.updateRunView()
+		return m.run.waitForOutput()
+	case runTickMsg:
+		// the title bar is rendered again with the run time
+		if msg.run != m.run || m.run.done {
+			return nil
+		}
+		return m.run.tick()
+	case runDoneMsg:
+		if msg.run != m.run {
+			return nil
+		}
+		m.run.done = true
+		m.run.duration = time.Since(m.run.start)
+		m.run.exitCode = msg.exitCode
+		var exitErr *exec.ExitError
+		if msg.err != nil && !errors.As(msg.err, &exitErr) {
+			m.run.err = msg.err
+		}
+		m.updateRunView()
+	}
+	return nil
+}
+
+// updateRunView writes the run output to the content viewport, following the
+// output while the viewport is scrolled to the bottom.
+func (m *Model) updateRunView() {
+	if m.run == nil || !m.run.visible {
+		return
+	}
+	atBottom := m.Code.AtBottom()
+	output := strings.Join(m.run.lines, "\n")
+	output = strings.ReplaceAll(output, "\t", strings.Repeat(" ", tabSpaces))
+	m.writeLineNumbers(len(m.run.lines) + 1)
+	m.Code.SetContent(output)
+	if atBottom {
+		m.Code.GotoBottom()
+		m.LineNumbers.GotoBottom()
+	}
+}
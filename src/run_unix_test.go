@@ -0,0 +1,57 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBlockRunKill(t *testing.T) {
+	t.Setenv("SHELL", "sh")
+	dir := t.TempDir()
+	r, err := startBlockRun("(sleep 1; touch started) & echo started; wait", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg, ok := r.waitForOutput()().(runOutputMsg); !ok || msg.line != "started" {
+		t.Fatalf("first output = %+v, want started", msg)
+	}
+	r.kill()
+
+	done := make(chan runDoneMsg)
+	go func() {
+		for {
+			if msg, ok := r.waitForOutput()().(runDoneMsg); ok {
+				done <- msg
+				return
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run is still waiting for its output after kill")
+	}
+	// the background subshell is killed with the shell
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(dir, "started")); !os.IsNotExist(err) {
+		t.Errorf("the background process of the run is still running: %v", err)
+	}
+}
+
+func TestBlockRunTick(t *testing.T) {
+	m := &Model{run: &blockRun{start: time.Now()}}
+	if cmd := m.updateRunOutput(runTickMsg{run: m.run}); cmd == nil {
+		t.Errorf("a running block stops ticking")
+	}
+	if cmd := m.updateRunOutput(runTickMsg{run: &blockRun{}}); cmd != nil {
+		t.Errorf("a replaced run keeps ticking")
+	}
+	m.run.done = true
+	if cmd := m.updateRunOutput(runTickMsg{run: m.run}); cmd != nil {
+		t.Errorf("a finished run keeps ticking")
+	}
+}
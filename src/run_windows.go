@@ -0,0 +1,13 @@
+//go:build windows
+
+package main
+
+import "os/exec"
+
+// setProcessGroup does nothing on Windows, only the shell is killed.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the command.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
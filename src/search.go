@@ -0,0 +1,493 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	searchMaxHits  = 50
+	searchMaxLines = 3
+
+	searchTitleScore = 10
+	searchCodeScore  = 3
+	searchProseScore = 1
+	searchLineScore  = 5
+)
+
+var (
+	searchPathStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	searchLineStyle     = lipgloss.NewStyle().PaddingLeft(4).Foreground(lipgloss.Color("245"))
+	searchTermStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+	searchSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	searchItemStyle     = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("252"))
+	searchEmptyStyle    = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("241"))
+)
+
+// searchLine is an indexed line of a section.
+type searchLine struct {
+	text  string
+	lower string
+	code  bool
+}
+
+// searchEntry is an indexed section of a snippet.
+type searchEntry struct {
+	snippet      Snippet
+	section      Section
+	sectionIndex int
+	title        string
+	lines        []searchLine
+}
+
+// searchIndex holds the title, prose and code lines of every section in the
+// repo.
+type searchIndex struct {
+	entries []searchEntry
+}
+
+// searchHit is a section matching the search terms.
+type searchHit struct {
+	Snippet      Snippet
+	Section      Section
+	SectionIndex int
+	Score        int
+	Lines        []string
+}
+
+// String returns the folder/file#section of the hit.
+func (h searchHit) String() string {
+	return h.Section.String()
+}
+
+// buildSearchIndex reads and indexes the sections of all snippets.
+func buildSearchIndex(config Config, snippets []Snippet) *searchIndex {
+	index := &searchIndex{}
+	for _, snippet := range snippets {
+		sections, err := readSections(config, snippet)
+		if err != nil {
+			continue
+		}
+		for i, section := range sections {
+			entry := searchEntry{
+				snippet:      snippet,
+				section:      section,
+				sectionIndex: i,
+				title:        strings.ToLower(section.Title),
+			}
+			for _, line := range proseLines(section.Content) {
+				entry.lines = append(entry.lines, newSearchLine(line, false))
+			}
+			for _, codeBlock := range section.CodeBlocks {
+				for _, line := range strings.Split(codeBlock.Content, "\n") {
+					entry.lines = append(entry.lines, newSearchLine(line, true))
+				}
+			}
+			index.entries = append(index.entries, entry)
+		}
+	}
+	return index
+}
+
+func newSearchLine(text string, code bool) searchLine {
+	text = strings.TrimSpace(text)
+	return searchLine{text: text, lower: strings.ToLower(text), code: code}
+}
+
+// proseLines returns the non-empty lines of the content outside of fenced
+// code blocks.
+func proseLines(content string) []string {
+	var lines []string
+	var fence string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			continue
+		}
+		if trimmed != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// fenceMarker returns the opening ``` or ~~~ run of a fenced code block line.
+func fenceMarker(line string) string {
+	for _, ch := range []string{"`", "~"} {
+		if strings.HasPrefix(line, ch+ch+ch) {
+			n := len(line) - len(strings.TrimLeft(line, ch))
+			return strings.Repeat(ch, n)
+		}
+	}
+	return ""
+}
+
+// search returns the sections matching all terms, ranked by score. Title
+// matches weigh more than code matches, which weigh more than prose.
+func (idx *searchIndex) search(query string, limit int) []searchHit {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var hits []searchHit
+	for _, entry := range idx.entries {
+		score := 0
+		matched := true
+		for _, term := range terms {
+			termScore := 0
+			if strings.Contains(entry.title, term) {
+				termScore += searchTitleScore
+			}
+			for _, line := range entry.lines {
+				if !strings.Contains(line.lower, term) {
+					continue
+				}
+				if line.code {
+					termScore += searchCodeScore
+				} else {
+					termScore += searchProseScore
+				}
+			}
+			if termScore == 0 {
+				matched = false
+				break
+			}
+			score += termScore
+		}
+		if !matched {
+			continue
+		}
+
+		var allTerms, someTerms []string
+		for _, line := range entry.lines {
+			count := 0
+			for _, term := range terms {
+				if strings.Contains(line.lower, term) {
+					count++
+				}
+			}
+			switch {
+			case count == len(terms):
+				score += searchLineScore
+				allTerms = append(allTerms, line.text)
+			case count > 0:
+				someTerms = append(someTerms, line.text)
+			}
+		}
+		lines := append(allTerms, someTerms...)
+		if len(lines) > searchMaxLines {
+			lines = lines[:searchMaxLines]
+		}
+
+		hits = append(hits, searchHit{
+			Snippet:      entry.snippet,
+			Section:      entry.section,
+			SectionIndex: entry.sectionIndex,
+			Score:        score,
+			Lines:        lines,
+		})
+	}
+
+	slices.SortStableFunc(hits, func(a, b searchHit) int {
+		return b.Score - a.Score
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// highlightTerms renders every case-insensitive occurrence of the terms in
+// the line with the given style.
+func highlightTerms(line, query string, style lipgloss.Style) string {
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		return line
+	}
+
+	marked := make([]bool, len(line))
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		for start := 0; ; {
+			i := strings.Index(lower[start:], term)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(term); j++ {
+				marked[j] = true
+			}
+			start += i + len(term)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		j := i
+		for j < len(line) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString(style.Render(line[i:j]))
+		} else {
+			b.WriteString(line[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// runSearch prints the sections matching the terms, or starts the search TUI
+// when no terms are given or --tui is set.
+func runSearch(config Config, snippets []Snippet, args []string) error {
+	parsed := parseArgs(args)
+	query := strings.Join(parsed.positional, " ")
+	index := buildSearchIndex(config, snippets)
+
+	if query == "" || parsed.has("tui") {
+		hit, err := runSearchProgram(index, query)
+		if err != nil || hit == nil {
+			return err
+		}
+		return runInteractiveMode(config, snippets, hit.Snippet, hit.Section)
+	}
+
+	hits := index.search(query, searchMaxHits)
+	if len(hits) == 0 {
+		return fmt.Errorf("no section matches %q", query)
+	}
+	for _, hit := range hits {
+		fmt.Println(searchPathStyle.Render(hit.String()))
+		for _, line := range hit.Lines {
+			fmt.Println(searchLineStyle.Render(highlightTerms(line, query, searchTermStyle)))
+		}
+	}
+	return nil
+}
+
+// searchChosenMsg is sent when a search result is chosen.
+type searchChosenMsg searchHit
+
+// searchClosedMsg is sent when the search is cancelled.
+type searchClosedMsg struct{}
+
+// searchModel is a text input with live search results.
+type searchModel struct {
+	input  textinput.Model
+	index  *searchIndex
+	hits   []searchHit
+	cursor int
+	height int
+}
+
+func newSearchModel(index *searchIndex, query string) searchModel {
+	input := textinput.New()
+	input.Prompt = "Search: "
+	input.PromptStyle = searchSelectedStyle
+	input.SetValue(query)
+	input.Focus()
+
+	m := searchModel{input: input, index: index, height: 20}
+	m.hits = index.search(query, searchMaxHits)
+	return m
+}
+
+func (m searchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m searchModel) Update(msg tea.Msg) (searchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height - 3
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, func() tea.Msg { return searchClosedMsg{} }
+		case "enter":
+			if m.cursor < len(m.hits) {
+				hit := m.hits[m.cursor]
+				return m, func() tea.Msg { return searchChosenMsg(hit) }
+			}
+			return m, nil
+		case "up", "ctrl+k", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+j", "ctrl+n":
+			if m.cursor < len(m.hits)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	query := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != query {
+		m.hits = m.index.search(m.input.Value(), searchMaxHits)
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+func (m searchModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.input.View() + "\n\n")
+
+	if len(m.hits) == 0 {
+		if strings.TrimSpace(m.input.Value()) != "" {
+			b.WriteString(searchEmptyStyle.Render("No matches"))
+		}
+		return b.String()
+	}
+
+	// each hit takes two lines, keep the cursor visible
+	visible := m.height / 2
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if m.cursor >= visible {
+		start = m.cursor - visible + 1
+	}
+	query := m.input.Value()
+	for i := start; i < len(m.hits) && i < start+visible; i++ {
+		hit := m.hits[i]
+		title := fmt.Sprintf("%s › %s", hit.Snippet.Path(), hit.Section.Title)
+		line := ""
+		if len(hit.Lines) > 0 {
+			line = hit.Lines[0]
+		}
+		if i == m.cursor {
+			b.WriteString(searchSelectedStyle.Render("> "+title) + "\n")
+		} else {
+			b.WriteString(searchItemStyle.Render(title) + "\n")
+		}
+		b.WriteString(searchLineStyle.Render(highlightTerms(line, query, searchTermStyle)) + "\n")
+	}
+	return b.String()
+}
+
+// searchProgram runs the searchModel as a standalone program.
+type searchProgram struct {
+	search searchModel
+	chosen *searchHit
+	quit   bool
+}
+
+func (p searchProgram) Init() tea.Cmd {
+	return p.search.Init()
+}
+
+func (p searchProgram) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case searchChosenMsg:
+		hit := searchHit(msg)
+		p.chosen = &hit
+		p.quit = true
+		return p, tea.Quit
+	case searchClosedMsg:
+		p.quit = true
+		return p, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	p.search, cmd = p.search.Update(msg)
+	return p, cmd
+}
+
+func (p searchProgram) View() string {
+	if p.quit {
+		return ""
+	}
+	return "\n" + p.search.View()
+}
+
+// runSearchProgram starts the search TUI and returns the chosen hit.
+func runSearchProgram(index *searchIndex, query string) (*searchHit, error) {
+	model, err := tea.NewProgram(searchProgram{search: newSearchModel(index, query)}, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, fmt.Errorf("run interactive program failed: %w", err)
+	}
+	if p, ok := model.(searchProgram); ok {
+		return p.chosen, nil
+	}
+	return nil, nil
+}
+
+// searchOverlay is the search of the application, it fills the screen
+// instead of the panes.
+type searchOverlay struct {
+	search searchModel
+}
+
+func (s *searchOverlay) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	var cmd tea.Cmd
+	s.search, cmd = s.search.Update(msg)
+	return s, cmd
+}
+
+func (s *searchOverlay) View() string {
+	return "\n" + s.search.View()
+}
+
+// openSearch opens the search across the sections of every snippet.
+func (m *Model) openSearch() tea.Cmd {
+	search := newSearchModel(buildSearchIndex(m.config, m.allSnippets()), "")
+	search.input.Cursor.SetMode(cursor.CursorStatic)
+	search.height = m.height + m.config.BaseMarginTop + 1
+	m.overlay = &searchOverlay{search: search}
+	return nil
+}
+
+// jumpTo selects the folder, the snippet and the section of the search hit.
+// The tag filter is cleared if it hides the snippet.
+func (m *Model) jumpTo(hit searchHit) tea.Cmd {
+	m.overlay = nil
+	for _, snippet := range m.hiddenSnippets {
+		if snippet.key() == hit.Snippet.key() {
+			m.filterTag("")
+			break
+		}
+	}
+
+	for i, item := range m.Folders.Items() {
+		if string(item.(Folder)) == hit.Snippet.Folder {
+			m.Folders.Select(i)
+			m.config.FolderName = hit.Snippet.Folder
+			break
+		}
+	}
+	snippets := m.Snippets()
+	if snippets == nil {
+		return m.showError(fmt.Errorf("snippet %s not found", hit.Snippet.Path()))
+	}
+	for i, item := range snippets.Items() {
+		if item.(Snippet).key() == hit.Snippet.key() {
+			snippets.Select(i)
+			break
+		}
+	}
+	m.selectSection(hit.Section.Slug)
+	m.blocks.active = false
+	m.pane = sectionPane
+	m.updateStyleByPane()
+	m.updateKeyMap()
+	return tea.Batch(m.updateContent(), m.scheduleSectionOpen())
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestSearch(t *testing.T) {
+	config := newTestRepo(t, map[string]string{
+		"k8s/pods.md": "# Pods\n\nList the workloads.\n\n---\n\n# Logs\n\nRead the logs of the pods.\n\n" +
+			"---\n\n# Exec\n\n```sh\nkubectl exec -it web -- sh\nkubectl get pods\n```\n",
+		"k8s/nodes.md": "# Nodes\n\n```sh\nkubectl get nodes\nkubectl describe pods\n```\n\n" +
+			"---\n\n# Drain\n\nDrain a node before maintenance.\n",
+	})
+	index := buildSearchIndex(config, loadSnippets(config))
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "title above code above prose",
+			query: "pods",
+			// ties keep the order of the snippets
+			want: []string{"k8s/pods.md#Pods", "k8s/nodes.md#Nodes", "k8s/pods.md#Exec", "k8s/pods.md#Logs"},
+		},
+		{
+			name:  "all terms on a line above terms on separate lines",
+			query: "get pods",
+			want:  []string{"k8s/pods.md#Exec", "k8s/nodes.md#Nodes"},
+		},
+		{
+			name:  "every term must match",
+			query: "drain pods",
+		},
+		{
+			name:  "case insensitive",
+			query: "KUBECTL Exec",
+			want:  []string{"k8s/pods.md#Exec"},
+		},
+		{
+			name:  "limit",
+			query: "pods",
+			limit: 2,
+			want:  []string{"k8s/pods.md#Pods", "k8s/nodes.md#Nodes"},
+		},
+		{
+			name:  "empty query",
+			query: "  ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, hit := range index.search(tt.query, tt.limit) {
+				got = append(got, hit.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchLines(t *testing.T) {
+	config := newTestRepo(t, map[string]string{
+		"k8s/pods.md": "# Pods\n\nThe pods of a node.\n\n```sh\nkubectl get pods\nkubectl get nodes\nkubectl top node\nkubectl get pods -o wide\n```\n",
+	})
+	hits := buildSearchIndex(config, loadSnippets(config)).search("get pods", 0)
+	if len(hits) != 1 {
+		t.Fatalf("search hits = %d, want 1", len(hits))
+	}
+	// lines with every term come first, at most searchMaxLines of them
+	want := []string{"kubectl get pods", "kubectl get pods -o wide", "# Pods"}
+	if !slices.Equal(hits[0].Lines, want) {
+		t.Errorf("hit lines = %q, want %q", hits[0].Lines, want)
+	}
+}
@@ -1,13 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/aquilax/truncate"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 // default values for empty state.
@@ -19,6 +26,18 @@ const (
 
 	metaKeyCopyable = "copyable"
 	metaKeyTitle    = "title"
+
+	headingAttributeTags = "tags"
+)
+
+// section_split values, the hr split divides at "---" lines and the h1 and h2
+// split divide at the headings up to the level.
+const (
+	sectionSplitHR = "hr"
+	sectionSplitH1 = "h1"
+	sectionSplitH2 = "h2"
+
+	sectionBreak = "---"
 )
 
 // Section represents a partial content of section in markdown file.
@@ -26,8 +45,26 @@ type Section struct {
 	Folder     string      `json:"folder"`
 	File       string      `json:"file"`
 	Title      string      `json:"title"`
+	Slug       string      `json:"slug"`
 	Content    string      `json:"content"`
 	CodeBlocks []CodeBlock `json:"code_blocks"`
+	Tags       []string    `json:"tags"`
+	// blockOrder is the index in CodeBlocks of every rendered code block, -1
+	// for an indented code block.
+	blockOrder []int
+}
+
+// renderedBlocks returns the index in CodeBlocks of every code block of the
+// rendered content, glamour renders indented code blocks too.
+func (s Section) renderedBlocks() []int {
+	if s.blockOrder != nil {
+		return s.blockOrder
+	}
+	order := make([]int, len(s.CodeBlocks))
+	for i := range order {
+		order[i] = i
+	}
+	return order
 }
 
 // CodeBlock represents a code block in a section.
@@ -35,6 +72,8 @@ type CodeBlock struct {
 	Content  string
 	Language string
 	Meta     map[string]string
+	// Index is the 1-based position in the section, 0 for a whole snippet.
+	Index int
 }
 
 // defaultSection is a section with all the default values, used for when
@@ -64,7 +103,7 @@ type Sections struct {
 
 // FilterValue is the section filter value that can be used when searching.
 func (s Section) FilterValue() string {
-	return s.Title + "\n"
+	return s.Title + "\n" + "+" + strings.Join(s.Tags, "+")
 	// return s.Folder + "/" + s.File + s.Title + s.Content + "\n"
 }
 
@@ -114,9 +153,9 @@ func (d sectionDelegate) Render(w io.Writer, m list.Model, index int, item list.
 	}
 
 	if index == m.Index() {
-		_, _ = fmt.Fprint(w, selectedItemStyle.Render("> "+truncate.Truncate(s.Title, 30, "...", truncate.PositionEnd)))
+		_, _ = fmt.Fprint(w, selectedItemStyle.Render("> "+withTagChips(s.Title, s.Tags, 30)))
 	} else {
-		_, _ = fmt.Fprint(w, unselectedItemStyle.Render(truncate.Truncate(s.Title, 30, "...", truncate.PositionEnd)))
+		_, _ = fmt.Fprint(w, unselectedItemStyle.Render(withTagChips(s.Title, s.Tags, 30)))
 	}
 }
 
@@ -129,3 +168,138 @@ func (s Sections) String(i int) string {
 func (s Sections) Len() int {
 	return len(s.sections)
 }
+
+// readSections reads the snippet file and splits it into sections.
+func readSections(config Config, snippet Snippet) ([]Section, error) {
+	content, err := os.ReadFile(config.getSnippetPath(snippet))
+	if err != nil {
+		return nil, err
+	}
+	return parseSections(snippet, string(content), config.SectionSplit), nil
+}
+
+// parseSections splits the snippet content into sections at the top level
+// "---" lines, or at the headings of the h1 and h2 split, and extracts the
+// title and code blocks of each section. The front matter is not a section.
+func parseSections(snippet Snippet, snippetContent string, split string) []Section {
+	_, body := splitFrontMatter(snippetContent)
+	source := []byte(body)
+	doc := newSectionParser(split).Parse(text.NewReader(source))
+
+	var sections []Section
+	var nodes []ast.Node
+	start := 0
+	addSection := func(end int) {
+		mdElem := &MarkdownElem{
+			CodeBlocks: make([]CodeBlock, 0),
+		}
+		for _, n := range nodes {
+			_ = mdElem.collect(n, source)
+		}
+		nodes = nil
+
+		// the heading attributes are not rendered
+		var content strings.Builder
+		pos := start
+		for _, attribute := range mdElem.attributes {
+			content.Write(source[pos:attribute.Start])
+			pos = attribute.Stop
+		}
+		content.Write(source[pos:end])
+		if strings.TrimSpace(content.String()) == "" {
+			return
+		}
+		sections = append(sections, Section{
+			Folder:     snippet.Folder,
+			File:       snippet.File,
+			Content:    strings.TrimSpace(content.String()),
+			Title:      mdElem.FirstTitle,
+			CodeBlocks: mdElem.CodeBlocks,
+			Tags:       mdElem.Tags,
+			blockOrder: mdElem.blockOrder,
+		})
+	}
+
+	// only the blocks of the document are checked, so a "---" or heading in a
+	// code block, list or quote never splits
+	level := sectionSplitLevel(split)
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		switch node := n.(type) {
+		case *ast.ThematicBreak:
+			if level == 0 && node.Lines().Len() > 0 {
+				segment := node.Lines().At(0)
+				addSection(segment.Start)
+				start = segment.Stop
+				continue
+			}
+		case *ast.Heading:
+			if level > 0 && node.Level <= level && node.Lines().Len() > 0 {
+				lineStart := bytes.LastIndexByte(source[:node.Lines().At(0).Start], '\n') + 1
+				addSection(lineStart)
+				start = lineStart
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	addSection(len(source))
+	setSectionSlugs(sections)
+	return sections
+}
+
+// sectionSplitLevel returns the heading level of the h1 and h2 split, or 0
+// for the hr split.
+func sectionSplitLevel(split string) int {
+	switch split {
+	case sectionSplitH1:
+		return 1
+	case sectionSplitH2:
+		return 2
+	}
+	return 0
+}
+
+// newSectionParser returns the markdown parser of the split, it parses the
+// {tags="k8s,debug"} attributes of the headings. The hr split
+// parses every "---" line as a thematic break, even right after a paragraph
+// where it would be a setext heading, so the sections of existing files are
+// kept.
+func newSectionParser(split string) parser.Parser {
+	options := []parser.Option{parser.WithAttribute()}
+	if sectionSplitLevel(split) == 0 {
+		options = append(options, parser.WithBlockParsers(util.Prioritized(sectionBreakParser{}, 99)))
+	}
+	return goldmark.New(goldmark.WithParserOptions(options...)).Parser()
+}
+
+// sectionBreakParser parses a "---" line of the document as a thematic break,
+// it keeps the line as the segment of the break.
+type sectionBreakParser struct{}
+
+func (p sectionBreakParser) Trigger() []byte {
+	return []byte{'-'}
+}
+
+func (p sectionBreakParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	if parent.Kind() != ast.KindDocument || strings.TrimRight(string(line), " \t\r\n") != sectionBreak {
+		return nil, parser.NoChildren
+	}
+	node := ast.NewThematicBreak()
+	node.Lines().Append(segment)
+	reader.Advance(segment.Len() - 1)
+	return node, parser.NoChildren
+}
+
+func (p sectionBreakParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (p sectionBreakParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (p sectionBreakParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p sectionBreakParser) CanAcceptIndentedLine() bool {
+	return false
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestParseSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		split   string
+		content string
+		titles  []string
+		blocks  [][]string
+	}{
+		{
+			name:    "hr split",
+			split:   sectionSplitHR,
+			content: "# Pods\n\n```bash\nkubectl get pods\n```\n\n---\n\n# Logs\n\n```bash\nkubectl logs pod\n```\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{{"kubectl get pods"}, {"kubectl logs pod"}},
+		},
+		{
+			name:    "hr split keeps headings in a section",
+			split:   sectionSplitHR,
+			content: "# Pods\n\n## Get\n\n```bash\nkubectl get pods\n```\n\n## Delete\n\n```bash\nkubectl delete pod\n```\n",
+			titles:  []string{"Pods"},
+			blocks:  [][]string{{"kubectl get pods", "kubectl delete pod"}},
+		},
+		{
+			name:    "hr split after a paragraph",
+			split:   sectionSplitHR,
+			content: "# Pods\n\nList the pods.\n---\n# Logs\n\nShow the logs.\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{nil, nil},
+		},
+		{
+			name:    "hr in a fenced block",
+			split:   sectionSplitHR,
+			content: "# Front Matter\n\n```yaml\n---\ntitle: Pods\n---\n```\n\n---\n\n# Logs\n\n```bash\nkubectl logs pod\n```\n",
+			titles:  []string{"Front Matter", "Logs"},
+			blocks:  [][]string{{"---\ntitle: Pods\n---"}, {"kubectl logs pod"}},
+		},
+		{
+			name:    "hr in a list or quote",
+			split:   sectionSplitHR,
+			content: "# Pods\n\n- one\n\n  ---\n\n> quote\n> ---\n",
+			titles:  []string{"Pods"},
+			blocks:  [][]string{nil},
+		},
+		{
+			name:    "front matter is not a section",
+			split:   sectionSplitHR,
+			content: "---\ntitle: Kubernetes\ntags: [k8s]\n---\n\n# Pods\n\n```bash\nkubectl get pods\n```\n\n---\n\n# Logs\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{{"kubectl get pods"}, nil},
+		},
+		{
+			name:    "empty sections are dropped",
+			split:   sectionSplitHR,
+			content: "---\n\n# Pods\n\n---\n\n---\n",
+			titles:  []string{"Pods"},
+			blocks:  [][]string{nil},
+		},
+		{
+			name:    "h1 split",
+			split:   sectionSplitH1,
+			content: "# Pods\n\n## Get\n\n```bash\nkubectl get pods\n```\n\n# Logs\n\n```bash\nkubectl logs pod\n```\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{{"kubectl get pods"}, {"kubectl logs pod"}},
+		},
+		{
+			name:    "h1 split ignores hr",
+			split:   sectionSplitH1,
+			content: "# Pods\n\nget\n\n---\n\ndelete\n\n# Logs\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{nil, nil},
+		},
+		{
+			name:    "h1 split keeps the intro",
+			split:   sectionSplitH1,
+			content: "Notes about pods.\n\n# Pods\n\n```bash\nkubectl get pods\n```\n",
+			titles:  []string{"", "Pods"},
+			blocks:  [][]string{nil, {"kubectl get pods"}},
+		},
+		{
+			name:    "h1 split with front matter",
+			split:   sectionSplitH1,
+			content: "---\ntitle: Kubernetes\n---\n# Pods\n\n# Logs\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{nil, nil},
+		},
+		{
+			name:    "h2 split",
+			split:   sectionSplitH2,
+			content: "# Kubernetes\n\n## Pods\n\n```bash\nkubectl get pods\n```\n\n### Delete\n\n```bash\nkubectl delete pod\n```\n\n## Logs\n\n```bash\nkubectl logs pod\n```\n",
+			titles:  []string{"Kubernetes", "Pods", "Logs"},
+			blocks:  [][]string{nil, {"kubectl get pods", "kubectl delete pod"}, {"kubectl logs pod"}},
+		},
+		{
+			name:    "h2 split at a setext heading",
+			split:   sectionSplitH2,
+			content: "Pods\n---\n\n```bash\nkubectl get pods\n```\n\nLogs\n---\n\n```bash\nkubectl logs pod\n```\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{{"kubectl get pods"}, {"kubectl logs pod"}},
+		},
+		{
+			name:    "h2 split ignores headings in a fenced block",
+			split:   sectionSplitH2,
+			content: "## Pods\n\n```md\n## Not a section\n---\n```\n\n## Logs\n",
+			titles:  []string{"Pods", "Logs"},
+			blocks:  [][]string{{"## Not a section\n---"}, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections := parseSections(Snippet{Folder: "k8s", File: "pods.md"}, tt.content, tt.split)
+			titles := make([]string, len(sections))
+			blocks := make([][]string, len(sections))
+			for i, section := range sections {
+				titles[i] = section.Title
+				for _, codeBlock := range section.CodeBlocks {
+					blocks[i] = append(blocks[i], codeBlock.Content)
+				}
+			}
+			if !slices.Equal(titles, tt.titles) {
+				t.Fatalf("parseSections titles = %q, want %q", titles, tt.titles)
+			}
+			for i := range blocks {
+				if !slices.Equal(blocks[i], tt.blocks[i]) {
+					t.Errorf("parseSections section %q code blocks = %q, want %q", titles[i], blocks[i], tt.blocks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSectionsContent(t *testing.T) {
+	content := "# Pods {tags=\"k8s\"}\n\nList the pods.\n\n---\n\n# Logs\n"
+	sections := parseSections(Snippet{Folder: "k8s", File: "pods.md"}, content, sectionSplitHR)
+	if len(sections) != 2 {
+		t.Fatalf("parseSections returned %d sections, want 2", len(sections))
+	}
+	if want := "# Pods \n\nList the pods."; sections[0].Content != want {
+		t.Errorf("first section content = %q, want %q", sections[0].Content, want)
+	}
+	if !slices.Equal(sections[0].Tags, []string{"k8s"}) {
+		t.Errorf("first section tags = %q, want [k8s]", sections[0].Tags)
+	}
+	if want := "# Logs"; sections[1].Content != want {
+		t.Errorf("second section content = %q, want %q", sections[1].Content, want)
+	}
+}
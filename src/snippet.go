@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/chroma/v2/quick"
@@ -46,6 +47,20 @@ type Snippet struct {
 	Name     string    `json:"title"`
 	File     string    `json:"file"`
 	Language string    `json:"language"`
+	// Name, Description, Tags, Aliases, Icon and Order are read from the
+	// front matter of the file.
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Order       int      `json:"order,omitempty"`
+	// Usage and SectionUsage, by section slug, count the opens and copies
+	// for the frecent sort. They are kept in the usage file of the user.
+	Usage        *usage            `json:"-"`
+	SectionUsage map[string]*usage `json:"-"`
+	// Repo is the name of the repo when the snippets of all repos are
+	// loaded, it is empty otherwise.
+	Repo string `json:"-"`
 }
 
 // SnippetsWrapper represents the root JSON structure that contains the snippet list
@@ -53,9 +68,23 @@ type SnippetsWrapper struct {
 	SnippetList []Snippet `json:"snippet_list"`
 }
 
-// String returns the folder/name.ext of the snippet.
+// String returns the folder/file of the snippet.
 func (s Snippet) String() string {
-	return fmt.Sprintf("%s/%s.%s", s.Folder, s.Name, s.Language)
+	return fmt.Sprintf("%s/%s", s.Folder, s.File)
+}
+
+// key identifies the snippet in the lists of the model, it includes the repo
+// when all repos are loaded.
+func (s Snippet) key() string {
+	return s.Repo + ":" + s.Path()
+}
+
+// Title returns the name of the snippet with its icon.
+func (s Snippet) Title() string {
+	if s.Icon == "" {
+		return s.Name
+	}
+	return s.Icon + " " + s.Name
 }
 
 // LegacyPath returns the legacy path <folder>-<file>
@@ -91,8 +120,7 @@ func (s Snippet) Content(highlight bool) string {
 
 // FilterValue is the snippet filter value that can be used when searching.
 func (s Snippet) FilterValue() string {
-	return s.Name + "\n"
-	//return s.Folder + "/" + s.Name + "\n" + "+" + strings.Join(s.Tags, "+") + "\n" + s.Language
+	return s.Name + "\n" + strings.Join(s.Aliases, "\n") + "\n" + s.Description + "\n" + "+" + strings.Join(s.Tags, "+")
 }
 
 // snippetDelegate represents the snippet list item.
@@ -142,13 +170,22 @@ func (d snippetDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		descStyle = d.styles.CopiedItemDesc
 	}
 
+	desc := s.Folder + " • " + humanizeTime(s.Date)
+	if s.Repo != "" {
+		desc = s.Repo + " • " + desc
+	}
+	if s.Description != "" {
+		desc += " • " + s.Description
+	}
+	desc = withTagChips(desc, s.Tags, 30)
+
 	if index == m.Index() {
-		_, _ = fmt.Fprintln(w, "  "+titleStyle.Render(truncate.Truncate(s.Name, 30, "...", truncate.PositionEnd)))
-		_, _ = fmt.Fprint(w, "  "+descStyle.Render(s.Folder+" • "+humanizeTime(s.Date)))
+		_, _ = fmt.Fprintln(w, "  "+titleStyle.Render(truncate.Truncate(s.Title(), 30, "...", truncate.PositionEnd)))
+		_, _ = fmt.Fprint(w, "  "+descStyle.Render(desc))
 		return
 	}
-	_, _ = fmt.Fprintln(w, "  "+d.styles.UnselectedItemTitle.Render(truncate.Truncate(s.Name, 30, "...", truncate.PositionEnd)))
-	_, _ = fmt.Fprint(w, "  "+d.styles.UnselectedItemDesc.Render(s.Folder+" • "+humanizeTime(s.Date)))
+	_, _ = fmt.Fprintln(w, "  "+d.styles.UnselectedItemTitle.Render(truncate.Truncate(s.Title(), 30, "...", truncate.PositionEnd)))
+	_, _ = fmt.Fprint(w, "  "+d.styles.UnselectedItemDesc.Render(desc))
 }
 
 var magnitudes = []humanize.RelTimeMagnitude{
@@ -177,9 +214,15 @@ type Snippets struct {
 	snippets []Snippet
 }
 
-// String returns the string of the snippet at the specified position i
+// String returns the string of the snippet at the specified position i, with
+// the title and aliases of its front matter.
 func (s Snippets) String(i int) string {
-	return s.snippets[i].String()
+	snippet := s.snippets[i]
+	values := []string{snippet.String()}
+	if snippet.Name != strings.TrimSuffix(snippet.File, filepath.Ext(snippet.File)) {
+		values = append(values, snippet.Name)
+	}
+	return strings.Join(append(values, snippet.Aliases...), " ")
 }
 
 // Len returns the length of the snippets array.
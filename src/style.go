@@ -37,11 +37,13 @@ type ContentStyle struct {
 // FoldersBaseStyle holds the necessary styling for the folders pane of
 // the application.
 type FoldersBaseStyle struct {
-	Base       lipgloss.Style
-	Title      lipgloss.Style
-	TitleBar   lipgloss.Style
-	Selected   lipgloss.Style
-	Unselected lipgloss.Style
+	Base           lipgloss.Style
+	Title          lipgloss.Style
+	TitleBar       lipgloss.Style
+	Selected       lipgloss.Style
+	Unselected     lipgloss.Style
+	CopiedTitleBar lipgloss.Style
+	ErrorTitleBar  lipgloss.Style
 }
 
 // SnippetsBaseStyle holds the necessary styling for the snippets pane of
@@ -57,6 +59,7 @@ type SnippetsBaseStyle struct {
 	CopiedTitleBar      lipgloss.Style
 	CopiedItemTitle     lipgloss.Style
 	CopiedItemDesc      lipgloss.Style
+	ErrorTitleBar       lipgloss.Style
 }
 
 // SectionsBaseStyle holds the necessary styling for the sections pane of
@@ -71,6 +74,7 @@ type SectionsBaseStyle struct {
 	UnselectedItemDesc  lipgloss.Style
 	CopiedTitleBar      lipgloss.Style
 	CopiedItemTitle     lipgloss.Style
+	ErrorTitleBar       lipgloss.Style
 }
 
 // ContentBaseStyle holds the necessary styling for the content pane of the
@@ -81,6 +85,7 @@ type ContentBaseStyle struct {
 	Separator      lipgloss.Style
 	LineNumber     lipgloss.Style
 	CopiedTitleBar lipgloss.Style
+	ErrorTitleBar  lipgloss.Style
 }
 
 // Styles is the struct of all styles for the application.
@@ -97,6 +102,41 @@ var helpStyle = lipgloss.NewStyle().Margin(0, 0, 0, 1)
 // DefaultStyles is the default implementation of the styles struct for all
 // styling in the application.
 func DefaultStyles(config Config) Styles {
+	// folders
+
+	folderBase := lipgloss.NewStyle().
+		Width(config.FolderTitleBarWidth + 3).
+		MarginTop(config.BaseMarginTop)
+
+	folderFocusedTitleBar := lipgloss.NewStyle().
+		Width(config.FolderTitleBarWidth).
+		Margin(SnippetBarMargin...).
+		Padding(TitlePadding...).
+		Background(lipgloss.Color(config.FocusedBarBgColor)).
+		Foreground(lipgloss.Color(config.FocusedBarFgColor))
+
+	folderBlurredTitleBar := folderFocusedTitleBar
+	folderBlurredTitleBar = folderBlurredTitleBar.
+		Background(lipgloss.Color(config.BlurredBarBgColor)).
+		Foreground(lipgloss.Color(config.BlurredBarFgColor))
+
+	folderSelectedItem := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(config.SelectedItemFgColor))
+
+	folderUnselectedItem := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(config.UnselectedItemFgColor))
+
+	folderCopiedTitleBar := lipgloss.NewStyle().
+		Width(config.FolderTitleBarWidth).
+		Margin(SnippetBarMargin...).
+		Padding(TitlePadding...).
+		Background(lipgloss.Color(config.CopiedBarBgColor)).
+		Foreground(lipgloss.Color(config.CopiedBarFgColor))
+
+	folderErrorTitleBar := folderCopiedTitleBar.Copy().
+		Background(lipgloss.Color(config.ErrorBarBgColor)).
+		Foreground(lipgloss.Color(config.ErrorBarFgColor))
+
 	// snippets
 
 	snippetBase := lipgloss.NewStyle().
@@ -134,6 +174,10 @@ func DefaultStyles(config Config) Styles {
 		Background(lipgloss.Color(config.CopiedBarBgColor)).
 		Foreground(lipgloss.Color(config.CopiedBarFgColor))
 
+	snippetErrorTitleBar := snippetCopiedTitleBar.Copy().
+		Background(lipgloss.Color(config.ErrorBarBgColor)).
+		Foreground(lipgloss.Color(config.ErrorBarFgColor))
+
 	snippetCopiedItem := snippetSelectedItem
 	snippetCopiedItem = snippetCopiedItem.
 		Foreground(lipgloss.Color(config.CopiedItemFgColor)).
@@ -172,6 +216,10 @@ func DefaultStyles(config Config) Styles {
 		Background(lipgloss.Color(config.CopiedBarBgColor)).
 		Foreground(lipgloss.Color(config.CopiedBarFgColor))
 
+	sectionErrorTitleBar := sectionCopiedTitleBar.Copy().
+		Background(lipgloss.Color(config.ErrorBarBgColor)).
+		Foreground(lipgloss.Color(config.ErrorBarFgColor))
+
 	sectionCopiedItem := sectionSelectedItem
 	sectionCopiedItem = sectionCopiedItem.
 		Foreground(lipgloss.Color(config.CopiedItemFgColor)).
@@ -203,6 +251,10 @@ func DefaultStyles(config Config) Styles {
 		Background(lipgloss.Color(config.CopiedBarBgColor)).
 		Foreground(lipgloss.Color(config.CopiedBarFgColor))
 
+	contentErrorTitleBar := contentCopiedTitleBar.Copy().
+		Background(lipgloss.Color(config.ErrorBarBgColor)).
+		Foreground(lipgloss.Color(config.ErrorBarFgColor))
+
 	// custom glamour style
 	glamourDarkStyle := styles.DarkStyleConfig
 	glamourDarkStyle.H1 = glamourDarkStyle.H2
@@ -211,6 +263,24 @@ func DefaultStyles(config Config) Styles {
 	glamourDarkStyle.CodeBlock.StylePrimitive.BlockSuffix = config.CodeBlockSuffixTemp + "\n"
 
 	return Styles{
+		Folders: FoldersStyle{
+			Focused: FoldersBaseStyle{
+				Base:           folderBase,
+				TitleBar:       folderFocusedTitleBar,
+				Selected:       folderSelectedItem,
+				Unselected:     folderUnselectedItem,
+				CopiedTitleBar: folderCopiedTitleBar,
+				ErrorTitleBar:  folderErrorTitleBar,
+			},
+			Blurred: FoldersBaseStyle{
+				Base:           folderBase,
+				TitleBar:       folderBlurredTitleBar,
+				Selected:       folderSelectedItem,
+				Unselected:     folderUnselectedItem,
+				CopiedTitleBar: folderCopiedTitleBar,
+				ErrorTitleBar:  folderErrorTitleBar,
+			},
+		},
 		Snippets: SnippetsStyle{
 			Focused: SnippetsBaseStyle{
 				Base:                snippetBase,
@@ -222,6 +292,7 @@ func DefaultStyles(config Config) Styles {
 				CopiedTitleBar:      snippetCopiedTitleBar,
 				CopiedItemTitle:     snippetCopiedItem,
 				CopiedItemDesc:      snippetCopiedItem,
+				ErrorTitleBar:       snippetErrorTitleBar,
 			},
 			Blurred: SnippetsBaseStyle{
 				Base:                snippetBase,
@@ -233,6 +304,7 @@ func DefaultStyles(config Config) Styles {
 				CopiedTitleBar:      snippetCopiedTitleBar,
 				CopiedItemTitle:     snippetCopiedItem,
 				CopiedItemDesc:      snippetCopiedItem,
+				ErrorTitleBar:       snippetErrorTitleBar,
 			},
 		},
 		Sections: SectionsStyle{
@@ -243,6 +315,7 @@ func DefaultStyles(config Config) Styles {
 				UnselectedItemTitle: sectionUnselectedItem,
 				CopiedTitleBar:      sectionCopiedTitleBar,
 				CopiedItemTitle:     sectionCopiedItem,
+				ErrorTitleBar:       sectionErrorTitleBar,
 			},
 			Blurred: SectionsBaseStyle{
 				Base:                sectionBase,
@@ -251,6 +324,7 @@ func DefaultStyles(config Config) Styles {
 				UnselectedItemTitle: sectionUnselectedItem,
 				CopiedTitleBar:      sectionCopiedTitleBar,
 				CopiedItemTitle:     sectionCopiedItem,
+				ErrorTitleBar:       sectionErrorTitleBar,
 			},
 		},
 		Content: ContentStyle{
@@ -259,12 +333,14 @@ func DefaultStyles(config Config) Styles {
 				TitleBar:       contentFocusedTitleBar,
 				LineNumber:     contentLineNumber,
 				CopiedTitleBar: contentCopiedTitleBar,
+				ErrorTitleBar:  contentErrorTitleBar,
 			},
 			Blurred: ContentBaseStyle{
 				Code:           contentCode,
 				TitleBar:       contentBlurredTitleBar,
 				LineNumber:     contentLineNumber,
 				CopiedTitleBar: contentCopiedTitleBar,
+				ErrorTitleBar:  contentErrorTitleBar,
 			},
 		},
 		Glamour: map[string]ansi.StyleConfig{
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aquilax/truncate"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	tagPickerHeight = 12
+	allTagsTitle    = "All"
+)
+
+var (
+	tagChipStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("109"))
+	tagPickerItemStyle  = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("252"))
+	tagPickerFocusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+)
+
+// appendTags adds the tags that are not in the list yet, tags are compared
+// case-insensitively and a leading # is dropped.
+func appendTags(tags []string, more ...string) []string {
+	for _, tag := range more {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" && !hasTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// hasTag reports whether the tag is in the list, case-insensitively.
+func hasTag(tags []string, tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSnippetsByTag returns the snippets with the tag.
+func filterSnippetsByTag(snippets []Snippet, tag string) []Snippet {
+	var filtered []Snippet
+	for _, snippet := range snippets {
+		if hasTag(snippet.Tags, tag) {
+			filtered = append(filtered, snippet)
+		}
+	}
+	return filtered
+}
+
+// tagCount is a tag and the number of snippets with it.
+type tagCount struct {
+	Name     string `json:"name"`
+	Snippets int    `json:"snippets"`
+}
+
+// countTags returns the tags of the snippets sorted by name.
+func countTags(snippets []Snippet) []tagCount {
+	counts := make(map[string]*tagCount)
+	for _, snippet := range snippets {
+		for _, tag := range snippet.Tags {
+			key := strings.ToLower(tag)
+			if counts[key] == nil {
+				counts[key] = &tagCount{Name: tag}
+			}
+			counts[key].Snippets++
+		}
+	}
+
+	keys := maps.Keys(counts)
+	slices.Sort(keys)
+	tags := make([]tagCount, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, *counts[key])
+	}
+	return tags
+}
+
+// listTags prints the tags with the number of snippets.
+func listTags(snippets []Snippet) {
+	for _, tag := range countTags(snippets) {
+		fmt.Printf("%s (%d)\n", tag.Name, tag.Snippets)
+	}
+}
+
+func listTagRecords(snippets []Snippet, format listFormat) error {
+	return writeRecords(os.Stdout, format, countTags(snippets),
+		[]string{"name", "snippets"},
+		func(r tagCount) []string {
+			return []string{r.Name, strconv.Itoa(r.Snippets)}
+		})
+}
+
+// withTagChips appends the #tag chips to the text. The text is truncated
+// first, so the chips stay visible within the width.
+func withTagChips(text string, tags []string, width int) string {
+	if len(tags) == 0 {
+		return truncate.Truncate(text, width, "...", truncate.PositionEnd)
+	}
+	chips := truncate.Truncate("#"+strings.Join(tags, " #"), width, "...", truncate.PositionEnd)
+	textWidth := width - lipgloss.Width(chips) - 1
+	if textWidth < 8 {
+		return tagChipStyle.Render(chips)
+	}
+	return truncate.Truncate(text, textWidth, "...", truncate.PositionEnd) + " " + tagChipStyle.Render(chips)
+}
+
+// tagSelectedMsg is sent when a tag is picked, an empty tag shows every
+// snippet.
+type tagSelectedMsg string
+
+// tagPicker is the overlay to pick the tag that narrows the snippets.
+type tagPicker struct {
+	tags    []tagCount
+	total   int
+	current string
+	input   textinput.Model
+	cursor  int
+}
+
+func newTagPicker(snippets []Snippet, current string) *tagPicker {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.PromptStyle = overlayTitleStyle
+	input.Placeholder = "tag"
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+	return &tagPicker{
+		tags:    countTags(snippets),
+		total:   len(snippets),
+		current: current,
+		input:   input,
+	}
+}
+
+// matches returns the All entry and the tags containing the input.
+func (p *tagPicker) matches() []tagCount {
+	query := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(p.input.Value()), "#"))
+	var tags []tagCount
+	if query == "" {
+		tags = append(tags, tagCount{Snippets: p.total})
+	}
+	for _, tag := range p.tags {
+		if strings.Contains(strings.ToLower(tag.Name), query) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (p *tagPicker) Update(msg tea.Msg) (overlay, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		matches := p.matches()
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return p, closeOverlay
+		case "up", "ctrl+p", "ctrl+k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case "down", "ctrl+n", "ctrl+j":
+			if p.cursor < len(matches)-1 {
+				p.cursor++
+			}
+			return p, nil
+		case "enter":
+			if len(matches) == 0 {
+				return p, nil
+			}
+			tag := matches[p.cursor].Name
+			return p, func() tea.Msg { return tagSelectedMsg(tag) }
+		}
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.cursor = 0
+	return p, cmd
+}
+
+func (p *tagPicker) View() string {
+	var b strings.Builder
+	b.WriteString(overlayTitleStyle.Render("Filter snippets by tag") + "\n\n")
+	b.WriteString(p.input.View() + "\n\n")
+
+	matches := p.matches()
+	if len(matches) == 0 {
+		b.WriteString(overlayHelpStyle.Render("No tags found") + "\n")
+	}
+	start := max(0, p.cursor-tagPickerHeight+1)
+	for i := start; i < len(matches) && i < start+tagPickerHeight; i++ {
+		tag := matches[i]
+		name := "#" + tag.Name
+		if tag.Name == "" {
+			name = allTagsTitle
+		}
+		if strings.EqualFold(tag.Name, p.current) {
+			name += " •"
+		}
+		line := fmt.Sprintf("%s (%d)", name, tag.Snippets)
+		if i == p.cursor {
+			b.WriteString(tagPickerFocusStyle.Render("> "+line) + "\n")
+			continue
+		}
+		b.WriteString(tagPickerItemStyle.Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + overlayHelpStyle.Render("↑/↓ move • enter filter • esc cancel"))
+	return overlayStyle.Render(b.String())
+}
+
+// pickTag opens the tag picker.
+func (m *Model) pickTag() tea.Cmd {
+	m.overlay = newTagPicker(m.allSnippets(), m.tag)
+	return nil
+}
+
+// filterTag shows only the snippets with the tag, an empty tag shows every
+// snippet again.
+func (m *Model) filterTag(tag string) tea.Cmd {
+	m.overlay = nil
+
+	snippets := m.allSnippets()
+	slices.SortStableFunc(snippets, compareSnippetOrder)
+	folders := make(map[Folder][]list.Item)
+	var hidden []Snippet
+	for _, snippet := range snippets {
+		if tag != "" && !hasTag(snippet.Tags, tag) {
+			hidden = append(hidden, snippet)
+			continue
+		}
+		folders[Folder(snippet.Folder)] = append(folders[Folder(snippet.Folder)], list.Item(snippet))
+	}
+	if len(folders) == 0 {
+		return m.showError(fmt.Errorf("no snippet is tagged #%s", tag))
+	}
+
+	selected := m.selectedFolder()
+	folderNames := maps.Keys(folders)
+	slices.SortFunc(folderNames, func(a, b Folder) int {
+		return compareFolders(string(a), string(b))
+	})
+	m.tag = tag
+	m.hiddenSnippets = hidden
+	m.SnippetsMap = make(map[Folder]*list.Model, len(folders))
+	folderItems := make([]list.Item, 0, len(folderNames))
+	selectedIdx := 0
+	for i, folder := range folderNames {
+		m.SnippetsMap[folder] = newList(folders[folder], m.height, m.SnippetStyle)
+		m.sortSnippets(m.SnippetsMap[folder])
+		folderItems = append(folderItems, folder)
+		if folder == selected {
+			selectedIdx = i
+		}
+	}
+	m.Folders.SetItems(folderItems)
+	m.Folders.Select(selectedIdx)
+	m.config.FolderName = string(m.selectedFolder())
+	return m.updateContent()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestAppendTags(t *testing.T) {
+	got := appendTags([]string{"k8s"}, "#debug", " K8S ", "", "#", "Debug", "net")
+	if want := []string{"k8s", "debug", "net"}; !slices.Equal(got, want) {
+		t.Errorf("appendTags = %q, want %q", got, want)
+	}
+}
+
+func TestFilterSnippetsByTag(t *testing.T) {
+	config := newTestRepo(t, map[string]string{
+		"k8s/pods.md":    "---\ntags: [k8s, Debug]\n---\n# Pods\n",
+		"k8s/logs.md":    "---\ntags: k8s\n---\n# Logs\n\n---\n\n## Pod Logs {tags=\"debug,logs\"}\n",
+		"k8s/nodes.md":   "# Nodes {tags=\"#k8s\"}\n",
+		"bash/ls.md":     "---\ntitle: List\n---\n# ls\n",
+		"bash/script.sh": "---\ntags: [debug]\n---\n",
+	})
+	snippets := loadSnippets(config)
+	files := func(snippets []Snippet) []string {
+		var files []string
+		for _, snippet := range snippets {
+			files = append(files, snippet.Folder+"/"+snippet.File)
+		}
+		slices.Sort(files)
+		return files
+	}
+
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"k8s", []string{"k8s/logs.md", "k8s/nodes.md", "k8s/pods.md"}},
+		{"debug", []string{"k8s/logs.md", "k8s/pods.md"}},
+		{"#DEBUG", []string{"k8s/logs.md", "k8s/pods.md"}},
+		{"logs", []string{"k8s/logs.md"}},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := files(filterSnippetsByTag(snippets, tt.tag)); !slices.Equal(got, tt.want) {
+			t.Errorf("filterSnippetsByTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+
+	// the first spelling of a tag is kept, logs.md comes before pods.md
+	want := []tagCount{{"debug", 2}, {"k8s", 3}, {"logs", 1}}
+	if got := countTags(snippets); !slices.Equal(got, want) {
+		t.Errorf("countTags = %+v, want %+v", got, want)
+	}
+}
+
+func TestFilterTag(t *testing.T) {
+	config := newTestRepo(t, map[string]string{
+		"k8s/pods.md": "---\ntags: [k8s]\n---\n# Pods\n",
+		"k8s/logs.md": "# Logs\n",
+		"bash/ls.md":  "# ls {tags=\"shell\"}\n",
+	})
+	config.FolderName = "k8s"
+	m := newModel(config, loadSnippets(config), Snippet{})
+	m.Init()
+
+	m.filterTag("shell")
+	if got := m.selectedFolder(); got != "bash" || len(m.Folders.Items()) != 1 {
+		t.Errorf("folders with #shell = %d, selected %q, want only bash", len(m.Folders.Items()), got)
+	}
+	m.filterTag("k8s")
+	if got := len(m.Snippets().Items()); got != 1 || m.selectedSnippet().File != "pods.md" {
+		t.Errorf("snippets with #k8s = %d, selected %q, want pods.md", got, m.selectedSnippet().File)
+	}
+	if m.filterTag("missing"); m.tag != "k8s" {
+		t.Errorf("tag after a missing tag = %q, want k8s kept", m.tag)
+	}
+	m.filterTag("")
+	if got := len(m.allSnippets()); got != 3 || len(m.Folders.Items()) != 2 {
+		t.Errorf("snippets without a tag = %d in %d folders, want 3 in 2", got, len(m.Folders.Items()))
+	}
+}